@@ -0,0 +1,115 @@
+package urlparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxLineLength is the longest line buffered by ParseReader,
+// a URL of DefaultMaxURLLength followed by CRLF.
+const maxLineLength = DefaultMaxURLLength + len("\r\n")
+
+// ParseReader reads r line by line, parses every line and invokes fn
+// with the result. Blank lines are skipped. Lines longer than
+// DefaultMaxURLLength are passed to fn as ErrURLTooLong without being
+// buffered in full. A read error is passed to fn with a nil URL and
+// stops the reading.
+func ParseReader(r io.Reader, fn func(*URL, error)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	scanner.Split(scanLimitedLines())
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) > DefaultMaxURLLength {
+			fn(nil, ErrURLTooLong)
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			fn(Parse(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fn(nil, err)
+	}
+}
+
+// scanLimitedLines is like bufio.ScanLines, but a line filling up
+// maxLineLength is returned truncated and the rest of it is discarded.
+func scanLimitedLines() bufio.SplitFunc {
+	discarding := false
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if discarding {
+			if i := bytes.IndexByte(data, '\n'); i != -1 {
+				discarding = false
+				return i + 1, nil, nil
+			}
+			return len(data), nil, nil
+		}
+
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance == 0 && token == nil && len(data) >= maxLineLength {
+			discarding = true
+			return len(data), data, nil
+		}
+		return advance, token, err
+	}
+}
+
+// ParseList parses URLs packed in to one field, separated by sep or by
+// whitespace when sep is empty. Entries are trimmed and empty ones are
+// skipped. The first parse error stops the parsing.
+func ParseList(s string, sep string) ([]*URL, error) {
+	var entries []string
+	if sep == "" {
+		entries = strings.Fields(s)
+	} else {
+		entries = strings.Split(s, sep)
+	}
+
+	urls := []*URL{}
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		u, err := Parse(entry)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// NormalizeBatch parses and normalizes urls on the given number of
+// goroutines, at least one. Results and errors are returned in the order
+// of urls, the error of a successfully normalized URL is nil.
+func NormalizeBatch(urls []string, workers int) ([]string, []error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]string, len(urls))
+	errs := make([]error, len(urls))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = NormalizeString(urls[i])
+			}
+		}()
+	}
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, errs
+}
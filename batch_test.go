@@ -0,0 +1,111 @@
+package urlparser_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+	Describe("ParseReader", func() {
+		It("should parse every non-blank line", func() {
+			input := "http://a.com/x\n\nhttps://b.com:8080/\r\n   \ngoogle.com:8080"
+			hosts := []string{}
+			ParseReader(strings.NewReader(input), func(url *URL, err error) {
+				Expect(err).ShouldNot(HaveOccurred())
+				hosts = append(hosts, url.Host)
+			})
+			Expect(hosts).Should(Equal([]string{"a.com", "b.com", "google.com"}))
+		})
+
+		It("should pass parse errors to callback", func() {
+			input := "http://a.com/\nhttp://b.com/" + strings.Repeat("a", DefaultMaxURLLength) + "\n"
+			errs := []error{}
+			ParseReader(strings.NewReader(input), func(url *URL, err error) {
+				errs = append(errs, err)
+			})
+			Expect(errs).Should(Equal([]error{nil, ErrURLTooLong}))
+		})
+
+		It("should skip too long line and go on", func() {
+			input := "http://a.com/\nhttp://b.com/" + strings.Repeat("a", 1<<20) + "\r\nhttp://c.com/"
+			hosts := []string{}
+			errs := []error{}
+			ParseReader(strings.NewReader(input), func(url *URL, err error) {
+				errs = append(errs, err)
+				if url != nil {
+					hosts = append(hosts, url.Host)
+				}
+			})
+			Expect(errs).Should(Equal([]error{nil, ErrURLTooLong, nil}))
+			Expect(hosts).Should(Equal([]string{"a.com", "c.com"}))
+		})
+
+		It("should accept line of max length with CRLF", func() {
+			raw := "http://b.com/" + strings.Repeat("a", DefaultMaxURLLength-len("http://b.com/"))
+			hosts := []string{}
+			ParseReader(strings.NewReader(raw+"\r\n"+raw), func(url *URL, err error) {
+				Expect(err).ShouldNot(HaveOccurred())
+				hosts = append(hosts, url.Host)
+			})
+			Expect(hosts).Should(Equal([]string{"b.com", "b.com"}))
+		})
+	})
+
+	Describe("ParseList", func() {
+		hosts := func(urls []*URL) []string {
+			result := []string{}
+			for _, url := range urls {
+				result = append(result, url.Host)
+			}
+			return result
+		}
+
+		It("should split on separator", func() {
+			urls, err := ParseList("http://a , http://b,, ", ",")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hosts(urls)).Should(Equal([]string{"a", "b"}))
+		})
+
+		It("should split on whitespace without separator", func() {
+			urls, err := ParseList(" http://a\n\thttp://b:8080/x  https://c ", "")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hosts(urls)).Should(Equal([]string{"a", "b", "c"}))
+		})
+
+		It("should return parse error", func() {
+			urls, err := ParseList("http://a;http://b/"+strings.Repeat("a", DefaultMaxURLLength), ";")
+			Expect(err).Should(Equal(ErrURLTooLong))
+			Expect(urls).Should(BeNil())
+		})
+	})
+
+	Describe("NormalizeBatch", func() {
+		It("should normalize concurrently keeping order", func() {
+			urls := []string{}
+			expected := []string{}
+			for i := 0; i < 100; i++ {
+				urls = append(urls, fmt.Sprintf("HTTP://Example.COM:80/%d/./a/../b?z=1&a=2", i))
+				expected = append(expected, fmt.Sprintf("http://example.com/%d/b?a=2&z=1", i))
+			}
+
+			results, errs := NormalizeBatch(urls, 8)
+			Expect(results).Should(Equal(expected))
+			Expect(errs).Should(HaveLen(len(urls)))
+			for _, err := range errs {
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should collect errors in order", func() {
+			urls := []string{"http://a.com/", "http://b.com/" + strings.Repeat("a", DefaultMaxURLLength), "http://c.com/"}
+			results, errs := NormalizeBatch(urls, 0)
+			Expect(results).Should(Equal([]string{"http://a.com/", "", "http://c.com/"}))
+			Expect(errs).Should(Equal([]error{nil, ErrURLTooLong, nil}))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package urlparser
+
+import (
+	"net"
+	"strings"
+)
+
+// Canonical returns the URL string in canonical form.
+// Unlike Normalize it's purell-free and doesn't mutate the URL.
+// Behavior:
+//  1. Lowercase the scheme and the host, percent-decode the host ("%65xample.com"
+//     becomes "example.com") unless it decodes to chars invalid in a host.
+//  2. Strip the trailing dot of the host ("example.com." becomes "example.com").
+//     Compress IPv6 host ("[2001:db8:0:0:0:0:0:1]" becomes "[2001:db8::1]").
+//  3. Decode percent-encoded unreserved characters in path, query and fragment ("%41" becomes "A").
+//  4. Uppercase percent-escapes in path, query and fragment ("%2f" becomes "%2F").
+//  5. Collapse duplicate slashes in path ("/a//b" becomes "/a/b"), the "//"
+//     authority marker is kept.
+func (u *URL) Canonical() string {
+	canonical := *u
+	canonical.canonicalize()
+	return canonical.String()
+}
+
+// CacheKey returns a stable key for caching the resource of the URL:
+// Canonical with explicit default port omitted, query sorted,
+// dot segments removed and fragment dropped.
+func (u *URL) CacheKey() string {
+	key := u.Clone()
+	key.canonicalize()
+	if key.HasDefaultPort() {
+		key.Port = ""
+	}
+	key.SortQuery()
+	key.CleanPath()
+	key.ClearFragment()
+	return key.String()
+}
+
+// canonicalize applies the Canonical steps to the URL in place.
+func (u *URL) canonicalize() {
+	u.Scheme = strings.ToLower(u.Scheme)
+	if host, err := decodeHost(u.Host); err == nil {
+		u.Host = host
+	}
+	u.Host = canonicalIPv6(trimHostDot(strings.ToLower(u.Host)))
+	u.Path = collapseSlashes(uppercaseEscapes(decodeUnreserved(u.Path)))
+	u.Query = uppercaseEscapes(decodeUnreserved(u.Query))
+	u.Fragment = uppercaseEscapes(decodeUnreserved(u.Fragment))
+}
+
+// decodeUnreserved decodes percent-encoded unreserved characters
+// (ALPHA / DIGIT / "-" / "." / "_" / "~"), RFC 3986 section 6.2.2.2.
+// Other escapes are left intact.
+func decodeUnreserved(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			if c := unhex(s[i+1])<<4 | unhex(s[i+2]); isUnreserved(c) {
+				buf.WriteByte(c)
+				i += 2
+				continue
+			}
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10
+	}
+	return 0
+}
+
+// collapseSlashes replaces runs of slashes in the path with a single one.
+func collapseSlashes(path string) string {
+	if !strings.Contains(path, "//") {
+		return path
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		buf.WriteByte(path[i])
+	}
+	return buf.String()
+}
+
+// uppercaseEscapes uppercases hex digits of percent-escapes without
+// decoding them, RFC 3986 section 6.2.2.1.
+func uppercaseEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	b := []byte(s)
+	for i := 0; i+2 < len(b); i++ {
+		if b[i] == '%' && isHex(b[i+1]) && isHex(b[i+2]) {
+			b[i+1] = upperHex(b[i+1])
+			b[i+2] = upperHex(b[i+2])
+			i += 2
+		}
+	}
+	return string(b)
+}
+
+func upperHex(c byte) byte {
+	if 'a' <= c && c <= 'f' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// canonicalIPv6 returns the IPv6 host in compressed form, RFC 5952.
+// IPv4-mapped addresses keep the "::ffff:" prefix, so they stay IPv6.
+func canonicalIPv6(host string) string {
+	if !strings.Contains(host, ":") {
+		return host
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return "::ffff:" + ip4.String()
+	}
+	return ip.String()
+}
+
+// trimHostDot strips a single trailing dot of an absolute DNS name,
+// unless nothing is left of the host.
+func trimHostDot(host string) string {
+	if len(host) > 1 && strings.HasSuffix(host, ".") {
+		return host[:len(host)-1]
+	}
+	return host
+}
@@ -0,0 +1,132 @@
+package urlparser_test
+
+import (
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canonical", func() {
+	It("should lowercase scheme and host", func() {
+		url, _ := Parse("HTTP://WWW.Google.COM/Path")
+		Expect(url.Canonical()).Should(Equal("http://www.google.com/Path"))
+	})
+
+	It("should strip trailing host dot", func() {
+		url, _ := Parse("http://example.com./path")
+		Expect(url.Host).Should(Equal("example.com."))
+		Expect(url.Canonical()).Should(Equal("http://example.com/path"))
+	})
+
+	It("should strip only single trailing host dot", func() {
+		url, _ := Parse("http://example.com../")
+		Expect(url.Canonical()).Should(Equal("http://example.com./"))
+	})
+
+	It("should not touch IP literals", func() {
+		url, _ := Parse("http://127.0.0.1:8080/")
+		Expect(url.Canonical()).Should(Equal("http://127.0.0.1:8080/"))
+
+		url, _ = Parse("http://[2001:db8::1]/")
+		Expect(url.Canonical()).Should(Equal("http://[2001:db8::1]/"))
+	})
+
+	It("should uppercase percent-escapes", func() {
+		url, _ := Parse("http://x/a%2fb%3Fc?q=%e2%82%ac#%5b")
+		Expect(url.Canonical()).Should(Equal("http://x/a%2Fb%3Fc?q=%E2%82%AC#%5B"))
+	})
+
+	It("should decode unreserved percent-escapes", func() {
+		url, _ := Parse("http://x/%41%42%43?q=%2d%5F#%7e")
+		Expect(url.Canonical()).Should(Equal("http://x/ABC?q=-_#~"))
+	})
+
+	It("should keep reserved percent-escapes", func() {
+		url, _ := Parse("http://x/%2F?q=%26%3d#%20")
+		Expect(url.Canonical()).Should(Equal("http://x/%2F?q=%26%3D#%20"))
+	})
+
+	It("should not touch malformed percent-escapes", func() {
+		url, _ := Parse("http://x/a%zf%2")
+		Expect(url.Canonical()).Should(Equal("http://x/a%zf%2"))
+	})
+
+	It("should decode percent-encoded host", func() {
+		url, _ := Parse("http://%65xample.COM/%41")
+		Expect(url.Canonical()).Should(Equal("http://example.com/A"))
+	})
+
+	It("should keep host decoding to invalid chars", func() {
+		for _, raw := range []string{"http://a%2fb.com/", "http://a%20b.com/", "http://a%zz.com/"} {
+			url, _ := Parse(raw)
+			Expect(url.Canonical()).Should(Equal(raw))
+		}
+	})
+
+	It("should compress IPv6 host", func() {
+		for raw, expected := range map[string]string{
+			"http://[2001:db8:0:0:0:0:0:1]/":  "http://[2001:db8::1]/",
+			"http://[2001:DB8::0:1]:8080/":    "http://[2001:db8::1]:8080/",
+			"http://[0:0:0:0:0:0:0:1]/":       "http://[::1]/",
+			"http://[::FFFF:192.168.0.1]/":    "http://[::ffff:192.168.0.1]/",
+			"http://[0:0:0:0:0:ffff:c0a8:1]/": "http://[::ffff:192.168.0.1]/",
+			"http://[2001:db8:0:1:1:1:1:1]/":  "http://[2001:db8:0:1:1:1:1:1]/",
+			"http://[fe80::1%25en0]/":         "http://[fe80::1%25en0]/",
+			"http://192.168.000.1/":           "http://192.168.000.1/",
+		} {
+			url, _ := Parse(raw)
+			Expect(url.Canonical()).Should(Equal(expected), raw)
+		}
+	})
+
+	It("should collapse duplicate slashes in path", func() {
+		url, _ := Parse("http://x/a//b")
+		Expect(url.Canonical()).Should(Equal("http://x/a/b"))
+
+		url, _ = Parse("http://x//a//b///c/?q=//#//")
+		Expect(url.Canonical()).Should(Equal("http://x/a/b/c/?q=//#//"))
+	})
+
+	It("should keep authority marker", func() {
+		url, _ := Parse("//x//a")
+		Expect(url.Canonical()).Should(Equal("//x/a"))
+
+		url, _ = Parse("file:///a//b")
+		Expect(url.Canonical()).Should(Equal("file:///a/b"))
+	})
+
+	It("should not mutate URL", func() {
+		url, _ := Parse("HTTP://Example.com./")
+		url.Canonical()
+		Expect(url.Scheme).Should(Equal("HTTP"))
+		Expect(url.Host).Should(Equal("Example.com."))
+	})
+
+	Describe("CacheKey", func() {
+		It("should produce the same key for equivalent URLs", func() {
+			a, _ := Parse("HTTP://Example.com:80/a/./b/../c?y=2&x=1#top")
+			b, _ := Parse("http://example.com/a/c?x=1&y=2")
+			Expect(a.CacheKey()).Should(Equal("http://example.com/a/c?x=1&y=2"))
+			Expect(a.CacheKey()).Should(Equal(b.CacheKey()))
+
+			a, _ = Parse("http://x/p?b=1&a=2&")
+			b, _ = Parse("http://x/p?a=2&&b=1")
+			Expect(a.CacheKey()).Should(Equal("http://x/p?a=2&b=1"))
+			Expect(b.CacheKey()).Should(Equal("http://x/p?a=2&b=1"))
+		})
+
+		It("should produce different keys for different URLs", func() {
+			for _, raw := range []string{
+				"http://example.com:8080/a/c?x=1&y=2",
+				"https://example.com/a/c?x=1&y=2",
+				"http://example.com/a/c?x=1&y=3",
+				"http://example.com/a/C?x=1&y=2",
+			} {
+				a, _ := Parse("http://example.com/a/c?x=1&y=2")
+				b, _ := Parse(raw)
+				Expect(a.CacheKey()).ShouldNot(Equal(b.CacheKey()), raw)
+			}
+		})
+	})
+})
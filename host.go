@@ -0,0 +1,189 @@
+package urlparser
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxDomainLength is the maximal length of a domain name, RFC 1035.
+const maxDomainLength = 253
+
+// ValidHost reports whether the host is a valid IP literal or a domain
+// name whose dot-separated labels are 1-63 chars long and don't start
+// or end with a hyphen, while the whole name is at most 253 chars.
+func (u *URL) ValidHost() bool {
+	if net.ParseIP(u.Host) != nil {
+		return true
+	}
+
+	host := trimHostDot(u.Host)
+	return len(host) <= maxDomainLength && domainRegexp.MatchString(host)
+}
+
+// HostMatches reports whether the host matches the pattern, both compared
+// in NormalizeHost form, i.e. ignoring case and trailing dot.
+// A leading "*." wildcard matches any number of subdomain labels, thus
+// "*.example.com" matches "a.example.com" and "a.b.example.com", but
+// neither "example.com" nor "evilexample.com".
+func (u *URL) HostMatches(pattern string) bool {
+	host := NormalizeHost(u.Host)
+
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := "." + NormalizeHost(pattern[2:])
+		return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+	}
+	return host == NormalizeHost(pattern)
+}
+
+// NormalizeHost returns the host in the form for comparison: lowercased,
+// without trailing dot and with Unicode labels encoded in to Punycode,
+// thus "WWW.Example.COM." becomes "www.example.com". The host the IDNA
+// can't encode is returned lowercased and without trailing dot only.
+func NormalizeHost(host string) string {
+	host = trimHostDot(strings.ToLower(nfcHost(host)))
+	if ascii, err := idna.ToASCII(host); err == nil {
+		return ascii
+	}
+	return host
+}
+
+// IsPrivateOrLoopback reports whether the IP literal host is in a loopback,
+// private (RFC 1918, RFC 4193), link-local or unspecified range, as an
+// SSRF guard. The zone of IPv6 literals like "fe80::1%25eth0" is ignored,
+// and shorthand or numeric IPv4 hosts ("127.1", "2130706433", "0177.0.0.1")
+// are decoded like HTTP clients do. Domain names would require DNS
+// resolution, so they return an error, except "localhost" and its
+// subdomains which are loopback.
+func (u *URL) IsPrivateOrLoopback() (bool, error) {
+	host := trimHostDot(strings.ToLower(u.Host))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true, nil
+	}
+	if i := strings.Index(host, "%"); i != -1 && strings.Contains(host, ":") {
+		host = host[:i]
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = parseNumericIPv4(host)
+	}
+	if ip == nil {
+		return false, fmt.Errorf("urlparser: host %q is not an IP literal", u.Host)
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified(), nil
+}
+
+// parseNumericIPv4 parses the IPv4 forms accepted by browsers and HTTP
+// clients, WHATWG URL section 3.5: up to four dot-separated decimal, octal
+// ("0177") or hex ("0x7f") parts, where the last part fills the remaining
+// bytes, thus "127.1" and "2130706433" are 127.0.0.1. Other hosts give nil.
+func parseNumericIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		base := 10
+		switch {
+		case len(part) > 2 && (part[:2] == "0x" || part[:2] == "0X"):
+			part, base = part[2:], 16
+		case len(part) > 1 && part[0] == '0':
+			part, base = part[1:], 8
+		}
+		value, err := strconv.ParseUint(part, base, 32)
+		if err != nil {
+			return nil
+		}
+		values[i] = value
+	}
+
+	var ip uint64
+	for _, value := range values[:len(values)-1] {
+		if value > 0xFF {
+			return nil
+		}
+		ip = ip<<8 | value
+	}
+	shift := uint(8 * (5 - len(values)))
+	last := values[len(values)-1]
+	if last >= 1<<shift {
+		return nil
+	}
+	ip = ip<<shift | last
+	return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip))
+}
+
+// RegistrableDomain returns the eTLD+1 of the host, e.g. "example.co.uk"
+// for "a.b.example.co.uk". IP hosts are returned as is.
+func (u *URL) RegistrableDomain() (string, error) {
+	host := trimHostDot(strings.ToLower(u.Host))
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	return publicsuffix.EffectiveTLDPlusOne(host)
+}
+
+// SameSite reports whether both URLs have the same scheme and
+// the same registrable domain ("schemeful same-site"), thus
+// "https://a.example.com" and "https://b.example.com" are same-site.
+func (u *URL) SameSite(other *URL) bool {
+	if !strings.EqualFold(u.Scheme, other.Scheme) {
+		return false
+	}
+
+	domain, err := u.RegistrableDomain()
+	if err != nil {
+		return false
+	}
+	otherDomain, err := other.RegistrableDomain()
+	return err == nil && domain == otherDomain
+}
+
+// HostUnicode returns the host with Punycode labels decoded in to Unicode,
+// the URL itself is not modified. The host is NFC-normalized first.
+func (u *URL) HostUnicode() (string, error) {
+	return idna.ToUnicode(nfcHost(u.Host))
+}
+
+// HostASCII returns the host with Unicode labels encoded in to Punycode,
+// the URL itself is not modified. The host is NFC-normalized first,
+// so composed and decomposed forms give the same result.
+func (u *URL) HostASCII() (string, error) {
+	return idna.ToASCII(nfcHost(u.Host))
+}
+
+// decodeHost percent-decodes the host before IDNA like WHATWG URL does,
+// thus "%65xample.com" becomes "example.com". Decoded ASCII bytes must be
+// reg-name chars, so "a%2Fb.com" is rejected. IPv6 hosts are kept as is.
+func decodeHost(host string) (string, error) {
+	if !strings.Contains(host, "%") || strings.Contains(host, ":") {
+		return host, nil
+	}
+
+	decoded, err := url.PathUnescape(host)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < len(decoded); i++ {
+		if c := decoded[i]; c < 0x80 && !isRegNameChar(c) {
+			return "", fmt.Errorf("urlparser: invalid character %q in decoded host %q", c, host)
+		}
+	}
+	return decoded, nil
+}
+
+// nfcHost applies Unicode normalization form C to the host,
+// e.g. "e" followed by combining acute accent becomes "é".
+func nfcHost(host string) string {
+	return norm.NFC.String(host)
+}
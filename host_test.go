@@ -0,0 +1,215 @@
+package urlparser_test
+
+import (
+	"strings"
+
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Host", func() {
+	Describe("ValidHost", func() {
+		It("should accept valid hosts", func() {
+			for _, raw := range []string{
+				"http://localhost/",
+				"http://www.google.com/",
+				"http://static.t-ru.org/",
+				"http://example.com./",
+				"http://127.0.0.1:8080/",
+				"http://[2001:db8:1f70::999:de8:7648:6e8]:9090/",
+				"http://" + strings.Repeat("a", 63) + ".com/",
+			} {
+				url, _ := Parse(raw)
+				Expect(url.ValidHost()).Should(BeTrue(), raw)
+			}
+		})
+
+		It("should reject invalid hosts", func() {
+			for _, raw := range []string{
+				"http://a-.com/",
+				"http://-a.com/",
+				"http://a..com/",
+				"http://exa_mple.com/",
+				"http://" + strings.Repeat("a", 64) + ".com/",
+				"http://" + strings.Repeat("a.", 127) + "com/",
+				"http:///path",
+			} {
+				url, _ := Parse(raw)
+				Expect(url.ValidHost()).Should(BeFalse(), raw)
+			}
+		})
+	})
+
+	Describe("HostMatches", func() {
+		It("should match wildcard and exact patterns", func() {
+			cases := []struct {
+				host, pattern string
+				matches       bool
+			}{
+				{"a.example.com", "*.example.com", true},
+				{"a.b.example.com", "*.example.com", true},
+				{"A.Example.COM", "*.example.com", true},
+				{"a.example.com", "*.EXAMPLE.com", true},
+				{"example.com", "*.example.com", false},
+				{"evilexample.com", "*.example.com", false},
+				{"example.com.evil.org", "*.example.com", false},
+				{"example.com", "example.com", true},
+				{"Example.Com", "example.com", true},
+				{"a.example.com", "example.com", false},
+				{"WWW.Example.COM.", "www.example.com", true},
+				{"a.example.com.", "*.example.com", true},
+				{"a.пример.рф", "*.xn--e1afmkfd.xn--p1ai", true},
+			}
+			for _, c := range cases {
+				url, _ := Parse("http://" + c.host + "/path")
+				Expect(url.HostMatches(c.pattern)).Should(Equal(c.matches), c.host+" vs "+c.pattern)
+			}
+		})
+	})
+
+	Describe("NormalizeHost", func() {
+		It("should lowercase, strip trailing dot and encode Punycode", func() {
+			for host, expected := range map[string]string{
+				"WWW.Example.COM.": "www.example.com",
+				"example.com":      "example.com",
+				"Пример.РФ":        "xn--e1afmkfd.xn--p1ai",
+				"127.0.0.1":        "127.0.0.1",
+				"":                 "",
+			} {
+				Expect(NormalizeHost(host)).Should(Equal(expected), host)
+			}
+		})
+	})
+
+	Describe("IsPrivateOrLoopback", func() {
+		It("should check IP literal hosts", func() {
+			for raw, expected := range map[string]bool{
+				"http://127.0.0.1":         true,
+				"http://127.1.2.3:8080/":   true,
+				"http://10.0.0.5":          true,
+				"http://172.16.0.1":        true,
+				"http://192.168.1.1":       true,
+				"http://169.254.169.254":   true,
+				"http://0.0.0.0":           true,
+				"http://[::1]":             true,
+				"http://[fc00::1]":         true,
+				"http://[fe80::1]":         true,
+				"http://LOCALHOST./":       true,
+				"http://a.localhost":       true,
+				"http://8.8.8.8":           false,
+				"http://172.32.0.1":        false,
+				"http://[2001:db8::1]":     false,
+				"http://[fe80::1%25eth0]/": true,
+				"http://[fe80::1%eth0]/":   true,
+				"http://[2001:db8::1%251]": false,
+				"http://127.1":             true,
+				"http://2130706433/":       true,
+				"http://0177.0.0.1":        true,
+				"http://0x7f.0.0.1":        true,
+				"http://0x7f000001":        true,
+				"http://10.1":              true,
+				"http://134744072":         false,
+			} {
+				url, _ := Parse(raw)
+				private, err := url.IsPrivateOrLoopback()
+				Expect(err).ShouldNot(HaveOccurred(), raw)
+				Expect(private).Should(Equal(expected), raw)
+			}
+		})
+
+		It("should fail for domain hosts", func() {
+			for _, raw := range []string{"http://example.com/", "http://1.2.3.4.5/", "http://127.256.0.1/", "http://0x/", "http://08.0.0.1/"} {
+				url, _ := Parse(raw)
+				_, err := url.IsPrivateOrLoopback()
+				Expect(err).Should(HaveOccurred(), raw)
+			}
+		})
+	})
+
+	Describe("RegistrableDomain", func() {
+		It("should return eTLD+1", func() {
+			for raw, domain := range map[string]string{
+				"http://a.b.example.com/":  "example.com",
+				"http://WWW.Example.COM./": "example.com",
+				"http://a.example.co.uk/":  "example.co.uk",
+				"http://127.0.0.1:8080/":   "127.0.0.1",
+			} {
+				url, _ := Parse(raw)
+				Expect(url.RegistrableDomain()).Should(Equal(domain), raw)
+			}
+		})
+
+		It("should fail for public suffix", func() {
+			url, _ := Parse("http://co.uk/")
+			_, err := url.RegistrableDomain()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("SameSite", func() {
+		It("should compare registrable domains", func() {
+			a, _ := Parse("https://a.example.com/x")
+			b, _ := Parse("https://b.example.com/y")
+			Expect(a.SameSite(b)).Should(BeTrue())
+
+			a, _ = Parse("https://example.com/")
+			b, _ = Parse("https://example.org/")
+			Expect(a.SameSite(b)).Should(BeFalse())
+		})
+
+		It("should compare schemes", func() {
+			a, _ := Parse("http://a.example.com/")
+			b, _ := Parse("HTTPS://b.example.com/")
+			Expect(a.SameSite(b)).Should(BeFalse())
+
+			a, _ = Parse("https://a.example.com/")
+			Expect(a.SameSite(b)).Should(BeTrue())
+		})
+	})
+
+	Describe("HostUnicode and HostASCII", func() {
+		It("should decode Punycode host and back", func() {
+			url, _ := Parse("http://xn--e1afmkfd.xn--p1ai/path")
+			host, err := url.HostUnicode()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(host).Should(Equal("пример.рф"))
+			Expect(url.Host).Should(Equal("xn--e1afmkfd.xn--p1ai"))
+
+			unicodeURL := url.WithHost(host)
+			host, err = unicodeURL.HostASCII()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(host).Should(Equal("xn--e1afmkfd.xn--p1ai"))
+			Expect(unicodeURL.Host).Should(Equal("пример.рф"))
+		})
+
+		It("should keep ASCII host as is", func() {
+			url, _ := Parse("http://www.google.com/")
+			host, err := url.HostUnicode()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(host).Should(Equal("www.google.com"))
+
+			host, err = url.HostASCII()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(host).Should(Equal("www.google.com"))
+		})
+
+		It("should treat composed and decomposed hosts equally", func() {
+			composed, _ := Parse("http://caf\u00e9.fr/")
+			decomposed, _ := Parse("http://cafe\u0301.fr/")
+			Expect(composed.Host).ShouldNot(Equal(decomposed.Host))
+
+			a, err := composed.HostASCII()
+			Expect(err).ShouldNot(HaveOccurred())
+			b, err := decomposed.HostASCII()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(a).Should(Equal("xn--caf-dma.fr"))
+			Expect(b).Should(Equal(a))
+
+			b, err = decomposed.HostUnicode()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(b).Should(Equal("caf\u00e9.fr"))
+		})
+	})
+})
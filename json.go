@@ -0,0 +1,77 @@
+package urlparser
+
+// URLJSON is the JSON view of URL components, so downstream tools
+// can read them without re-parsing the URL string.
+type URLJSON struct {
+	Input       string `json:"input,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	DoubleSlash string `json:"double_slash,omitempty"`
+	Opaque      string `json:"opaque,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	PasswordSet bool   `json:"password_set,omitempty"`
+	Authority   string `json:"authority,omitempty"`
+	Host        string `json:"host,omitempty"`
+	Port        string `json:"port,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Query       string `json:"query,omitempty"`
+	Fragment    string `json:"fragment,omitempty"`
+	ForceQuery  bool   `json:"force_query,omitempty"`
+	Relative    bool   `json:"relative,omitempty"`
+
+	QueryEncoding     QueryEncoding `json:"query_encoding,omitempty"`
+	PlusInPathIsSpace bool          `json:"plus_in_path_is_space,omitempty"`
+}
+
+// JSONComponents returns the JSON view of the URL components.
+func (u *URL) JSONComponents() URLJSON {
+	c := URLJSON{
+		Input:       u.Input,
+		Scheme:      u.Scheme,
+		DoubleSlash: u.DoubleSlash,
+		Opaque:      u.Opaque,
+		Authority:   u.Authority,
+		Host:        u.Host,
+		Port:        u.Port,
+		Path:        u.Path,
+		Query:       u.Query,
+		Fragment:    u.Fragment,
+		ForceQuery:  u.ForceQuery,
+		Relative:    u.Relative,
+
+		QueryEncoding:     u.QueryEncoding,
+		PlusInPathIsSpace: u.plusInPathIsSpace,
+	}
+	if u.User != nil {
+		c.Username = u.User.Username
+		c.Password = u.User.Password
+		c.PasswordSet = u.User.PasswordSet
+	}
+	return c
+}
+
+// URL converts the JSON view back in to URL.
+func (c URLJSON) URL() *URL {
+	return &URL{
+		Input:       c.Input,
+		Scheme:      c.Scheme,
+		DoubleSlash: c.DoubleSlash,
+		Opaque:      c.Opaque,
+		User: &Userinfo{
+			Username:    c.Username,
+			Password:    c.Password,
+			PasswordSet: c.PasswordSet,
+		},
+		Authority:  c.Authority,
+		Host:       c.Host,
+		Port:       c.Port,
+		Path:       c.Path,
+		Query:      c.Query,
+		Fragment:   c.Fragment,
+		ForceQuery: c.ForceQuery,
+		Relative:   c.Relative,
+
+		QueryEncoding:     c.QueryEncoding,
+		plusInPathIsSpace: c.PlusInPathIsSpace,
+	}
+}
@@ -0,0 +1,166 @@
+package urlparser
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// RemoveDotSegments removes "." and ".." segments from the path as
+// described in RFC 3986 section 5.2.4. A trailing "." or ".." segment
+// leaves the trailing slash in place: "/a/b/.." becomes "/a/".
+func RemoveDotSegments(path string) string {
+	var output []string
+	input := path
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, "../"):
+			input = input[3:]
+		case strings.HasPrefix(input, "./"):
+			input = input[2:]
+		case strings.HasPrefix(input, "/./"):
+			input = input[2:]
+		case input == "/.":
+			input = "/"
+		case strings.HasPrefix(input, "/../"):
+			input = input[3:]
+			output = popSegment(output)
+		case input == "/..":
+			input = "/"
+			output = popSegment(output)
+		case input == "." || input == "..":
+			input = ""
+		default:
+			// move the first segment, with its leading slash if any, to the output
+			end := strings.Index(input[1:], "/")
+			if end == -1 {
+				end = len(input)
+			} else {
+				end++
+			}
+			output = append(output, input[:end])
+			input = input[end:]
+		}
+	}
+	return strings.Join(output, "")
+}
+
+func popSegment(output []string) []string {
+	if len(output) == 0 {
+		return output
+	}
+	return output[:len(output)-1]
+}
+
+// CleanPath removes dot segments from u.Path in place,
+// the host, scheme and query are left untouched.
+func (u *URL) CleanPath() {
+	u.Path = RemoveDotSegments(u.Path)
+}
+
+// LowercasePath lowercases u.Path in place for case-insensitive servers.
+// Hex digits of percent-escapes are kept as is, so "/A%2Fb" becomes "/a%2Fb".
+func (u *URL) LowercasePath() {
+	u.Path = lowercasePath(u.Path)
+}
+
+func lowercasePath(s string) string {
+	b := []byte(s)
+	for i := 0; i < len(b); i++ {
+		if b[i] == '%' && i+2 < len(b) && isHex(b[i+1]) && isHex(b[i+2]) {
+			i += 2
+			continue
+		}
+		if 'A' <= b[i] && b[i] <= 'Z' {
+			b[i] += 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// Extension returns the lowercased extension of the last path segment,
+// e.g. ".html" for "/a/b.HTML?x=1", or "" when there is none.
+// The segment is percent-decoded first.
+func (u *URL) Extension() string {
+	segment := u.LastSegment()
+	if decoded, err := url.PathUnescape(segment); err == nil {
+		segment = decoded
+	}
+	return strings.ToLower(path.Ext(segment))
+}
+
+// PathSegments splits the raw path on "/", the empty element before
+// the leading slash is dropped: "/a/b/c.txt" becomes ["a", "b", "c.txt"].
+func (u *URL) PathSegments() []string {
+	if u.Path == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+}
+
+// DecodedPathSegments is like PathSegments but percent-decodes every
+// segment, so "/a%2Fb/c" becomes ["a/b", "c"].
+func (u *URL) DecodedPathSegments() ([]string, error) {
+	segments := u.PathSegments()
+	for i, segment := range segments {
+		decoded, err := u.pathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = decoded
+	}
+	return segments, nil
+}
+
+// LastSegment returns the raw last path segment, i.e. the file name.
+func (u *URL) LastSegment() string {
+	return u.Path[strings.LastIndex(u.Path, "/")+1:]
+}
+
+// JoinPath returns a copy of the URL with the elements joined to its
+// path, like url.URL.JoinPath: "http://x/a".JoinPath("b", "c") gives
+// "http://x/a/b/c". Duplicate slashes and dot segments are cleaned,
+// a trailing slash of the last element is kept. Characters not allowed
+// in a path are percent-encoded, existing escapes are kept as is.
+func (u *URL) JoinPath(elem ...string) *URL {
+	elem = append([]string{u.Path}, elem...)
+	for i := range elem {
+		elem[i] = escapePath(elem[i])
+	}
+
+	var joined string
+	if !strings.HasPrefix(elem[0], "/") {
+		// the leading slash keeps ".." from escaping a relative path
+		elem[0] = "/" + elem[0]
+		joined = path.Join(elem...)[1:]
+	} else {
+		joined = path.Join(elem...)
+	}
+	if strings.HasSuffix(elem[len(elem)-1], "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	if u.DoubleSlash != "" && joined != "" && !strings.HasPrefix(joined, "/") {
+		// path-abempty after authority
+		joined = "/" + joined
+	}
+
+	clone := u.Clone()
+	clone.Path = joined
+	return clone
+}
+
+// escapePath percent-encodes bytes not allowed in a path,
+// valid percent-escapes are left intact.
+func escapePath(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isPathChar(c) || c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			buf.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&buf, "%%%02X", c)
+	}
+	return buf.String()
+}
@@ -0,0 +1,178 @@
+package urlparser_test
+
+import (
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Path", func() {
+	Describe("RemoveDotSegments", func() {
+		It("should match RFC 3986 examples", func() {
+			Expect(RemoveDotSegments("/a/b/c/./../../g")).Should(Equal("/a/g"))
+			Expect(RemoveDotSegments("mid/content=5/../6")).Should(Equal("mid/6"))
+		})
+
+		It("should match RFC 3986 reference resolution examples", func() {
+			for path, expected := range map[string]string{
+				"/a/b/c/./g":           "/a/b/c/g",
+				"/a/b/c/g/":            "/a/b/c/g/",
+				"/a/b/c/.":             "/a/b/c/",
+				"/a/b/c/./":            "/a/b/c/",
+				"/a/b/c/..":            "/a/b/",
+				"/a/b/c/../":           "/a/b/",
+				"/a/b/c/../g":          "/a/b/g",
+				"/a/b/c/../..":         "/a/",
+				"/a/b/c/../../g":       "/a/g",
+				"/a/b/c/../../../g":    "/g",
+				"/a/b/c/../../../../g": "/g",
+				"/./g":                 "/g",
+				"/../g":                "/g",
+				"/a/b/c/g.":            "/a/b/c/g.",
+				"/a/b/c/.g":            "/a/b/c/.g",
+				"/a/b/c/g..":           "/a/b/c/g..",
+				"/a/b/c/..g":           "/a/b/c/..g",
+				"/a/b/c/./../g":        "/a/b/g",
+				"/a/b/c/g/../h":        "/a/b/c/h",
+				"":                     "",
+				"/":                    "/",
+			} {
+				Expect(RemoveDotSegments(path)).Should(Equal(expected), path)
+			}
+		})
+	})
+
+	Describe("CleanPath", func() {
+		It("should collapse path only", func() {
+			url, _ := Parse("HTTP://Example.com/a/./b/../c?q=/./x#/../f")
+			url.CleanPath()
+			Expect(url.Path).Should(Equal("/a/c"))
+			Expect(url.String()).Should(Equal("HTTP://Example.com/a/c?q=/./x#/../f"))
+		})
+
+		It("should preserve trailing slash", func() {
+			url, _ := Parse("http://x/a/b/..")
+			url.CleanPath()
+			Expect(url.Path).Should(Equal("/a/"))
+		})
+	})
+
+	Describe("LowercasePath", func() {
+		It("should lowercase path keeping escapes", func() {
+			for raw, expected := range map[string]string{
+				"http://X/Foo/Bar?Q=A#F": "http://X/foo/bar?Q=A#F",
+				"http://x/a%2Fb":         "http://x/a%2Fb",
+				"http://x/A%2fB%":        "http://x/a%2fb%",
+			} {
+				url, _ := Parse(raw)
+				url.LowercasePath()
+				Expect(url.String()).Should(Equal(expected), raw)
+			}
+		})
+
+		It("should lowercase path with option", func() {
+			url, _ := ParseWithOptions("http://x/Foo/Bar", Options{LowercasePath: true})
+			Expect(url.Path).Should(Equal("/foo/bar"))
+
+			url, _ = ParseWithOptions("Index.HTML", Options{LowercasePath: true})
+			Expect(url.Path).Should(Equal("./index.html"))
+		})
+	})
+
+	Describe("Extension", func() {
+		It("should return lowercased extension of last segment", func() {
+			for raw, expected := range map[string]string{
+				"http://x/a/b.HTML?x=1#y.css": ".html",
+				"http://x/app.min.js":         ".js",
+				"/style.css":                  ".css",
+				"index.php":                   ".php",
+				"http://x/a/":                 "",
+				"http://x/a.dir/file":         "",
+				"http://x":                    "",
+				"http://x/file%2Etxt":         ".txt",
+			} {
+				url, _ := Parse(raw)
+				Expect(url.Extension()).Should(Equal(expected), raw)
+			}
+		})
+	})
+
+	Describe("PathSegments", func() {
+		It("should split path in to segments", func() {
+			url, _ := Parse("http://x/a/b/c.txt?q=1")
+			Expect(url.PathSegments()).Should(Equal([]string{"a", "b", "c.txt"}))
+			Expect(url.LastSegment()).Should(Equal("c.txt"))
+		})
+
+		It("should keep trailing empty segment", func() {
+			url, _ := Parse("http://x/a/")
+			Expect(url.PathSegments()).Should(Equal([]string{"a", ""}))
+			Expect(url.LastSegment()).Should(Equal(""))
+		})
+
+		It("should handle empty path", func() {
+			url, _ := Parse("http://x")
+			Expect(url.PathSegments()).Should(BeEmpty())
+			Expect(url.LastSegment()).Should(Equal(""))
+		})
+
+		It("should optionally decode segments", func() {
+			url, _ := Parse("http://x/a%2Fb/c%20d.txt")
+			Expect(url.PathSegments()).Should(Equal([]string{"a%2Fb", "c%20d.txt"}))
+
+			segments, err := url.DecodedPathSegments()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(segments).Should(Equal([]string{"a/b", "c d.txt"}))
+
+			url, _ = Parse("http://x/a%zz")
+			_, err = url.DecodedPathSegments()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("JoinPath", func() {
+		It("should join elements like net/url", func() {
+			for _, test := range []struct {
+				base string
+				elem []string
+				out  string
+			}{
+				{"http://x/a", []string{"b", "c"}, "http://x/a/b/c"},
+				{"https://go.googlesource.com", []string{"go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com/a/b/c", []string{"../../../go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com/", []string{"../go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com", []string{"../go", "../../go", "../../../go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com/../go", nil, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com/", []string{"./go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com//", []string{"/go"}, "https://go.googlesource.com/go"},
+				{"https://go.googlesource.com//", []string{"/go", "a", "b", "c"}, "https://go.googlesource.com/go/a/b/c"},
+				{"https://go.googlesource.com", []string{"go/"}, "https://go.googlesource.com/go/"},
+				{"https://go.googlesource.com", []string{"go//"}, "https://go.googlesource.com/go/"},
+				{"https://go.googlesource.com", nil, "https://go.googlesource.com/"},
+				{"https://go.googlesource.com/", nil, "https://go.googlesource.com/"},
+				{"https://go.googlesource.com/a%2fb", []string{"c"}, "https://go.googlesource.com/a%2fb/c"},
+				{"https://go.googlesource.com/a%2fb", []string{"c%2fd"}, "https://go.googlesource.com/a%2fb/c%2fd"},
+				{"https://go.googlesource.com/a/b", []string{"/go"}, "https://go.googlesource.com/a/b/go"},
+				{"/", nil, "/"},
+				{"a", []string{"b"}, "a/b"},
+				{"a", []string{"../b"}, "b"},
+				{"a", []string{"../../b"}, "b"},
+			} {
+				url, _ := Parse(test.base)
+				Expect(url.JoinPath(test.elem...).String()).Should(Equal(test.out), test.base)
+			}
+		})
+
+		It("should percent-encode elements", func() {
+			url, _ := Parse("http://x/api?v=1")
+			Expect(url.JoinPath("a b", "c#d").String()).Should(Equal("http://x/api/a%20b/c%23d?v=1"))
+		})
+
+		It("should not mutate URL", func() {
+			url, _ := Parse("http://x/a")
+			url.JoinPath("b")
+			Expect(url.Path).Should(Equal("/a"))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package urlparser
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultPorts maps schemes to their well-known ports, guarded by defaultPortsMu.
+var (
+	defaultPortsMu sync.RWMutex
+	defaultPorts   = map[string]int{
+		"coap":    5683,
+		"coaps":   5684,
+		"ftp":     21,
+		"gopher":  70,
+		"http":    80,
+		"https":   443,
+		"ldap":    389,
+		"ldaps":   636,
+		"mongodb": 27017,
+		"redis":   6379,
+		"rtsp":    554,
+		"rtsps":   322,
+		"sftp":    22,
+		"sip":     5060,
+		"sips":    5061,
+		"telnet":  23,
+		"ws":      80,
+		"wss":     443,
+	}
+)
+
+// RegisterDefaultPort registers the default port of the scheme,
+// replacing the existing one. It's safe for concurrent use.
+func RegisterDefaultPort(scheme string, port int) {
+	defaultPortsMu.Lock()
+	defer defaultPortsMu.Unlock()
+	defaultPorts[strings.ToLower(scheme)] = port
+}
+
+// DefaultPort returns the default port of the scheme.
+func DefaultPort(scheme string) (int, bool) {
+	defaultPortsMu.RLock()
+	defer defaultPortsMu.RUnlock()
+	port, ok := defaultPorts[strings.ToLower(scheme)]
+	return port, ok
+}
+
+// defaultPort returns the default port of the scheme or "" when unknown.
+func defaultPort(scheme string) string {
+	if port, ok := DefaultPort(scheme); ok {
+		return strconv.Itoa(port)
+	}
+	return ""
+}
+
+// HasDefaultPort reports whether the URL explicitly includes the default
+// port of its scheme, like "http://x:80".
+func (u *URL) HasDefaultPort() bool {
+	port := defaultPort(u.Scheme)
+	return u.Port != "" && port != "" && comparePorts(u.Port, port) == 0
+}
+
+// effectivePort returns the explicit port or the default port of the scheme.
+func (u *URL) effectivePort() string {
+	if u.Port != "" {
+		return u.Port
+	}
+	return defaultPort(u.Scheme)
+}
+
+// Origin returns the origin tuple of the URL as "scheme://host:port",
+// with lowercased scheme and the host in NormalizeHost form. The port
+// is always shown: when it's omitted in the URL, the default port of
+// the scheme is used, thus "http://x/path" has "http://x:80" origin.
+// URLs without scheme or authority (opaque and relative ones) have
+// "null" origin.
+func (u *URL) Origin() string {
+	if u.Scheme == "" || u.DoubleSlash == "" || u.Host == "" {
+		return "null"
+	}
+
+	origin := URL{
+		Host: NormalizeHost(u.Host),
+		Port: u.effectivePort(),
+	}
+	return strings.ToLower(u.Scheme) + "://" + origin.hostPort()
+}
+
+// SameOrigin reports whether u and other share the origin: the scheme,
+// the case-insensitive host and the effective port are equal.
+// URLs with "null" origin are never same origin.
+func (u *URL) SameOrigin(other *URL) bool {
+	origin := u.Origin()
+	return origin != "null" && origin == other.Origin()
+}
@@ -0,0 +1,170 @@
+package urlparser_test
+
+import (
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ports", func() {
+	Describe("DefaultPort", func() {
+		It("should know less common schemes", func() {
+			for scheme, expected := range map[string]int{
+				"gopher":  70,
+				"sftp":    22,
+				"ldap":    389,
+				"ldaps":   636,
+				"redis":   6379,
+				"mongodb": 27017,
+				"telnet":  23,
+			} {
+				port, ok := DefaultPort(scheme)
+				Expect(ok).Should(BeTrue())
+				Expect(port).Should(Equal(expected))
+			}
+		})
+
+		It("should know coap schemes", func() {
+			url, _ := Parse("coap://device/sensor")
+			Expect(url.Scheme).Should(Equal("coap"))
+			Expect(url.Host).Should(Equal("device"))
+			Expect(url.Path).Should(Equal("/sensor"))
+			Expect(url.Port).Should(Equal(""))
+			Expect(url.Origin()).Should(Equal("coap://device:5683"))
+
+			url, _ = Parse("coaps://device:6000/sensor")
+			Expect(url.Scheme).Should(Equal("coaps"))
+			Expect(url.Host).Should(Equal("device"))
+			Expect(url.Path).Should(Equal("/sensor"))
+			Expect(url.Port).Should(Equal("6000"))
+
+			port, ok := DefaultPort("coaps")
+			Expect(ok).Should(BeTrue())
+			Expect(port).Should(Equal(5684))
+		})
+
+		It("should resolve custom registered scheme", func() {
+			_, ok := DefaultPort("myproto")
+			Expect(ok).Should(BeFalse())
+
+			RegisterDefaultPort("MyProto", 7777)
+			port, ok := DefaultPort("myproto")
+			Expect(ok).Should(BeTrue())
+			Expect(port).Should(Equal(7777))
+
+			url, _ := Parse("myproto://x/")
+			Expect(url.Origin()).Should(Equal("myproto://x:7777"))
+		})
+	})
+
+	Describe("Uppercase schemes", func() {
+		It("should keep scheme but resolve default port case-insensitively", func() {
+			url, err := Parse("HTTPS://X/")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("HTTPS"))
+			Expect(url.Host).Should(Equal("X"))
+
+			port, ok := DefaultPort(url.Scheme)
+			Expect(ok).Should(BeTrue())
+			Expect(port).Should(Equal(443))
+			Expect(url.Origin()).Should(Equal("https://x:443"))
+		})
+
+		It("should look up registered schemes case-insensitively", func() {
+			url, _ := Parse("WSS://x/chat")
+			Expect(url.IsWebSocket()).Should(BeTrue())
+
+			url, _ = Parse("MAILTO:user@host")
+			Expect(url.Scheme).Should(Equal("MAILTO"))
+			Expect(url.Opaque).Should(Equal("user@host"))
+			Expect(url.Host).Should(Equal(""))
+
+			url, _ = ParseWithOptions("HTTP:example.com/path", Options{ForceAuthorityForKnownSchemes: true})
+			Expect(url.Scheme).Should(Equal("HTTP"))
+			Expect(url.Host).Should(Equal("example.com"))
+		})
+	})
+
+	Describe("HasDefaultPort", func() {
+		It("should detect redundant default port", func() {
+			for raw, expected := range map[string]bool{
+				"http://x:80":    true,
+				"https://x:443/": true,
+				"HTTP://x:80":    true,
+				"http://x:8080":  false,
+				"https://x:80":   false,
+				"http://x":       false,
+				"foo://x:80":     false,
+			} {
+				url, _ := Parse(raw)
+				Expect(url.HasDefaultPort()).Should(Equal(expected), raw)
+			}
+		})
+	})
+
+	Describe("Origin", func() {
+		It("should use default port of scheme", func() {
+			url, _ := Parse("http://x/path")
+			Expect(url.Origin()).Should(Equal("http://x:80"))
+
+			url, _ = Parse("https://x/path")
+			Expect(url.Origin()).Should(Equal("https://x:443"))
+		})
+
+		It("should keep explicit port", func() {
+			url, _ := Parse("http://x:8080/path?q=1#f")
+			Expect(url.Origin()).Should(Equal("http://x:8080"))
+		})
+
+		It("should lowercase scheme and host", func() {
+			url, _ := Parse("HTTP://WWW.Example.COM/")
+			Expect(url.Origin()).Should(Equal("http://www.example.com:80"))
+		})
+
+		It("should keep IPv6 host in brackets", func() {
+			url, _ := Parse("http://[::1]/")
+			Expect(url.Origin()).Should(Equal("http://[::1]:80"))
+		})
+
+		It("should omit port of unknown scheme", func() {
+			url, _ := Parse("foo://x/")
+			Expect(url.Origin()).Should(Equal("foo://x"))
+		})
+
+		It("should return null for opaque and relative URLs", func() {
+			for _, raw := range []string{"mailto:mike@mike.mike", "mailto:/webmaster@golang.org", "/path", "viewtopic", "//x/path"} {
+				url, _ := Parse(raw)
+				Expect(url.Origin()).Should(Equal("null"))
+			}
+		})
+	})
+
+	Describe("SameOrigin", func() {
+		It("should compare scheme, host and effective port", func() {
+			cases := []struct {
+				a, b string
+				same bool
+			}{
+				{"http://x", "http://x:80", true},
+				{"http://x/a?q=1", "http://x/b#f", true},
+				{"http://X.com", "http://x.COM/", true},
+				{"http://x.com.", "http://X.COM/", true},
+				{"http://пример.рф", "http://xn--e1afmkfd.xn--p1ai", true},
+				{"https://x:443/", "https://x", true},
+				{"http://x", "https://x", false},
+				{"http://x:80", "https://x:80", false},
+				{"http://x", "http://x:8080", false},
+				{"http://x:8080", "http://x:8081", false},
+				{"http://x", "http://y", false},
+				{"/path", "/path", false},
+			}
+			for _, c := range cases {
+				a, _ := Parse(c.a)
+				b, _ := Parse(c.b)
+				Expect(a.SameOrigin(b)).Should(Equal(c.same), c.a+" vs "+c.b)
+				Expect(b.SameOrigin(a)).Should(Equal(c.same), c.b+" vs "+c.a)
+			}
+		})
+	})
+})
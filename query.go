@@ -0,0 +1,256 @@
+package urlparser
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryEncoding selects how the query serializers escape keys and values.
+type QueryEncoding int
+
+const (
+	// FormEncoding is application/x-www-form-urlencoded, space becomes "+".
+	FormEncoding QueryEncoding = iota
+	// RFC3986Encoding percent-encodes space as "%20".
+	RFC3986Encoding
+)
+
+// QueryPair is a single decoded key/value pair of the query.
+// HasEquals distinguishes a key without value ("a") from
+// a key with empty value ("a=").
+type QueryPair struct {
+	Key       string
+	Value     string
+	HasEquals bool
+}
+
+// QueryPairs splits the query into decoded pairs keeping their order.
+// Like url.URL.Query it's lenient: what can't be decoded is kept raw.
+func (u *URL) QueryPairs() []QueryPair {
+	return parseQueryPairs(u.Query)
+}
+
+// QueryValues returns the decoded query as url.Values.
+// Keys without value are mapped to empty values.
+func (u *URL) QueryValues() url.Values {
+	values := url.Values{}
+	for _, pair := range u.QueryPairs() {
+		values.Add(pair.Key, pair.Value)
+	}
+	return values
+}
+
+// FragmentValues parses the fragment like a query, as the OAuth
+// implicit flow responses do: "#access_token=x&expires_in=3600".
+func (u *URL) FragmentValues() url.Values {
+	values := url.Values{}
+	for _, pair := range parseQueryPairs(u.Fragment) {
+		values.Add(pair.Key, pair.Value)
+	}
+	return values
+}
+
+// QueryGet returns the first decoded value of the key, or "" when
+// there is none. Keys are matched as is, so PHP-style "a[]" and
+// "obj[key]" are distinct keys.
+func (u *URL) QueryGet(key string) string {
+	for _, pair := range u.QueryPairs() {
+		if pair.Key == key {
+			return pair.Value
+		}
+	}
+	return ""
+}
+
+// QueryInt returns the value of the key as int,
+// ok is false when it's missing or unparseable.
+func (u *URL) QueryInt(key string) (int, bool) {
+	value, err := strconv.Atoi(u.QueryGet(key))
+	return value, err == nil
+}
+
+// QueryFloat returns the value of the key as float64,
+// ok is false when it's missing or unparseable.
+func (u *URL) QueryFloat(key string) (float64, bool) {
+	value, err := strconv.ParseFloat(u.QueryGet(key), 64)
+	return value, err == nil
+}
+
+// QueryBool returns the value of the key as bool, accepting "1", "true",
+// "yes" and "0", "false", "no" in any case. ok is false when it's missing
+// or unparseable.
+func (u *URL) QueryBool(key string) (value bool, ok bool) {
+	switch strings.ToLower(u.QueryGet(key)) {
+	case "1", "true", "yes":
+		return true, true
+	case "0", "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// SetQueryValues encodes values in to u.Query sorted by key,
+// using u.QueryEncoding.
+func (u *URL) SetQueryValues(values url.Values) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []QueryPair{}
+	for _, key := range keys {
+		for _, value := range values[key] {
+			pairs = append(pairs, QueryPair{Key: key, Value: value, HasEquals: true})
+		}
+	}
+	u.SetQueryPairs(pairs)
+}
+
+// SetQueryPairs encodes pairs in to u.Query keeping their order,
+// using u.QueryEncoding. Pairs without HasEquals are written without "=".
+func (u *URL) SetQueryPairs(pairs []QueryPair) {
+	u.Query = encodeQueryPairs(pairs, u.QueryEncoding)
+}
+
+// QuerySet sets the key to the single value, using u.QueryEncoding.
+// It replaces the first parameter with the key and removes the rest,
+// or appends the parameter when there is none. Other parameters keep
+// their order and raw encoding.
+func (u *URL) QuerySet(key, value string) {
+	param := queryEscape(key, u.QueryEncoding) + "=" + queryEscape(value, u.QueryEncoding)
+
+	parts := []string{}
+	set := false
+	for _, part := range strings.Split(u.Query, "&") {
+		if part == "" {
+			continue
+		}
+		if queryUnescape(strings.SplitN(part, "=", 2)[0]) == key {
+			if !set {
+				parts = append(parts, param)
+				set = true
+			}
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if !set {
+		parts = append(parts, param)
+	}
+	u.Query = strings.Join(parts, "&")
+}
+
+// SortQuery sorts the query parameters by key in place. The sort is stable,
+// so parameters with duplicate keys keep their order, and the raw encoding
+// of every parameter is preserved. Empty parameters like in "a=1&&b=2&"
+// are dropped.
+func (u *URL) SortQuery() {
+	if u.Query == "" {
+		return
+	}
+
+	parts := []string{}
+	keys := map[string]string{}
+	for _, part := range strings.Split(u.Query, "&") {
+		if part == "" {
+			continue
+		}
+		parts = append(parts, part)
+		key := part
+		if i := strings.Index(part, "="); i != -1 {
+			key = part[:i]
+		}
+		keys[part] = queryUnescape(key)
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		return keys[parts[i]] < keys[parts[j]]
+	})
+	u.Query = strings.Join(parts, "&")
+}
+
+// CanonicalQuery returns the query in a deterministic form for signing
+// and comparison: every key and value is decoded, the pairs are sorted by
+// key, then by value and then "a" goes before "a=", and re-encoded with
+// RFC3986Encoding. Unlike SortQuery it doesn't preserve the raw encoding
+// and, unlike QueryPairs, it fails on malformed escapes.
+func (u *URL) CanonicalQuery() (string, error) {
+	pairs := []QueryPair{}
+	for _, part := range strings.Split(u.Query, "&") {
+		if part == "" {
+			continue
+		}
+		pair := QueryPair{Key: part}
+		if i := strings.Index(part, "="); i != -1 {
+			pair.Key, pair.Value, pair.HasEquals = part[:i], part[i+1:], true
+		}
+		var err error
+		if pair.Key, err = url.QueryUnescape(pair.Key); err != nil {
+			return "", err
+		}
+		if pair.Value, err = url.QueryUnescape(pair.Value); err != nil {
+			return "", err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Key != pairs[j].Key {
+			return pairs[i].Key < pairs[j].Key
+		}
+		if pairs[i].Value != pairs[j].Value {
+			return pairs[i].Value < pairs[j].Value
+		}
+		return !pairs[i].HasEquals && pairs[j].HasEquals
+	})
+	return encodeQueryPairs(pairs, RFC3986Encoding), nil
+}
+
+func parseQueryPairs(query string) []QueryPair {
+	pairs := []QueryPair{}
+	for _, part := range strings.Split(query, "&") {
+		if part == "" {
+			continue
+		}
+		pair := QueryPair{Key: part}
+		if i := strings.Index(part, "="); i != -1 {
+			pair.Key, pair.Value, pair.HasEquals = part[:i], part[i+1:], true
+		}
+		pair.Key = queryUnescape(pair.Key)
+		pair.Value = queryUnescape(pair.Value)
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+func encodeQueryPairs(pairs []QueryPair, encoding QueryEncoding) string {
+	parts := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		part := queryEscape(pair.Key, encoding)
+		if pair.HasEquals {
+			part += "=" + queryEscape(pair.Value, encoding)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "&")
+}
+
+// queryEscape escapes s for the query, literal "+" is always
+// escaped, so it's safe to turn the "+" spaces in to "%20".
+func queryEscape(s string, encoding QueryEncoding) string {
+	escaped := url.QueryEscape(s)
+	if encoding == RFC3986Encoding {
+		escaped = strings.Replace(escaped, "+", "%20", -1)
+	}
+	return escaped
+}
+
+// queryUnescape decodes s or returns it raw when it's malformed.
+func queryUnescape(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
@@ -0,0 +1,229 @@
+package urlparser_test
+
+import (
+	"net/url"
+
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	Describe("QueryPairs", func() {
+		It("should distinguish no value from empty value", func() {
+			u, _ := Parse("http://x/?a&b=&c=d")
+			Expect(u.QueryPairs()).Should(Equal([]QueryPair{
+				{Key: "a", Value: "", HasEquals: false},
+				{Key: "b", Value: "", HasEquals: true},
+				{Key: "c", Value: "d", HasEquals: true},
+			}))
+		})
+
+		It("should decode pairs and keep their order", func() {
+			u, _ := Parse("http://x/?b=go+language&a=%20&b=2")
+			Expect(u.QueryPairs()).Should(Equal([]QueryPair{
+				{Key: "b", Value: "go language", HasEquals: true},
+				{Key: "a", Value: " ", HasEquals: true},
+				{Key: "b", Value: "2", HasEquals: true},
+			}))
+		})
+
+		It("should keep malformed escapes raw", func() {
+			u, _ := Parse("http://x/?a=%zz")
+			Expect(u.QueryPairs()).Should(Equal([]QueryPair{{Key: "a", Value: "%zz", HasEquals: true}}))
+		})
+
+		It("should re-serialize pairs faithfully", func() {
+			u, _ := Parse("http://x/?a&b=&c=d")
+			u.SetQueryPairs(u.QueryPairs())
+			Expect(u.Query).Should(Equal("a&b=&c=d"))
+		})
+	})
+
+	Describe("QueryValues", func() {
+		It("should return decoded values", func() {
+			u, _ := Parse("http://x/?a&b=&c=d&c=e+f")
+			Expect(u.QueryValues()).Should(Equal(url.Values{
+				"a": {""},
+				"b": {""},
+				"c": {"d", "e f"},
+			}))
+		})
+	})
+
+	Describe("FragmentValues", func() {
+		It("should parse OAuth implicit flow response", func() {
+			u, _ := Parse("https://app.example.com/callback#access_token=x%2By&token_type=bearer&expires_in=3600&state=a+b")
+			values := u.FragmentValues()
+			Expect(values.Get("access_token")).Should(Equal("x+y"))
+			Expect(values.Get("expires_in")).Should(Equal("3600"))
+			Expect(values.Get("state")).Should(Equal("a b"))
+			Expect(u.QueryValues()).Should(BeEmpty())
+		})
+
+		It("should return empty values without fragment", func() {
+			u, _ := Parse("https://app.example.com/callback?code=1")
+			Expect(u.FragmentValues()).Should(BeEmpty())
+		})
+	})
+
+	Describe("Bracket keys", func() {
+		It("should keep array keys intact", func() {
+			u, _ := Parse("http://x/?a[]=1&a[]=2&a%5B%5D=3")
+			Expect(u.QueryValues()).Should(Equal(url.Values{"a[]": {"1", "2", "3"}}))
+			Expect(u.QueryGet("a[]")).Should(Equal("1"))
+			Expect(u.QueryGet("a")).Should(Equal(""))
+		})
+
+		It("should keep object keys intact", func() {
+			u, _ := Parse("http://x/?obj[key]=v&obj[other][]=w")
+			Expect(u.QueryGet("obj[key]")).Should(Equal("v"))
+			Expect(u.QueryGet("obj[other][]")).Should(Equal("w"))
+			Expect(u.QueryGet("missing")).Should(Equal(""))
+		})
+	})
+
+	Describe("Typed getters", func() {
+		raw := "http://x/?i=42&n=-7&f=2.5&e=1e3&b1=1&b0=0&bt=TRUE&bf=false&by=yes&bn=No&bad=x&empty="
+
+		It("should convert ints", func() {
+			u, _ := Parse(raw)
+			for key, expected := range map[string]int{"i": 42, "n": -7, "b1": 1} {
+				value, ok := u.QueryInt(key)
+				Expect(ok).Should(BeTrue(), key)
+				Expect(value).Should(Equal(expected), key)
+			}
+			for _, key := range []string{"f", "bad", "empty", "missing"} {
+				_, ok := u.QueryInt(key)
+				Expect(ok).Should(BeFalse(), key)
+			}
+		})
+
+		It("should convert floats", func() {
+			u, _ := Parse(raw)
+			for key, expected := range map[string]float64{"f": 2.5, "e": 1000, "i": 42} {
+				value, ok := u.QueryFloat(key)
+				Expect(ok).Should(BeTrue(), key)
+				Expect(value).Should(Equal(expected), key)
+			}
+			for _, key := range []string{"bad", "empty", "missing"} {
+				_, ok := u.QueryFloat(key)
+				Expect(ok).Should(BeFalse(), key)
+			}
+		})
+
+		It("should convert bools", func() {
+			u, _ := Parse(raw)
+			for key, expected := range map[string]bool{"b1": true, "b0": false, "bt": true, "bf": false, "by": true, "bn": false} {
+				value, ok := u.QueryBool(key)
+				Expect(ok).Should(BeTrue(), key)
+				Expect(value).Should(Equal(expected), key)
+			}
+			for _, key := range []string{"i", "bad", "empty", "missing"} {
+				_, ok := u.QueryBool(key)
+				Expect(ok).Should(BeFalse(), key)
+			}
+		})
+	})
+
+	Describe("SetQueryValues", func() {
+		It("should encode values sorted by key", func() {
+			u, _ := Parse("http://x/path#f")
+			u.SetQueryValues(url.Values{"b": {"go language"}, "a": {"1", "&"}})
+			Expect(u.Query).Should(Equal("a=1&a=%26&b=go+language"))
+			Expect(u.String()).Should(Equal("http://x/path?a=1&a=%26&b=go+language#f"))
+		})
+
+		It("should round-trip through QueryValues", func() {
+			values := url.Values{"q": {"go language", "a=b"}, "empty": {""}, "ключ": {"значение"}}
+			u, _ := Parse("http://x/")
+			u.SetQueryValues(values)
+			Expect(u.QueryValues()).Should(Equal(values))
+		})
+	})
+
+	Describe("QuerySet", func() {
+		It("should replace the parameter keeping others", func() {
+			u, _ := Parse("http://x/?a=1&b=%20&a=2&c")
+			u.QuerySet("a", "x y")
+			Expect(u.Query).Should(Equal("a=x+y&b=%20&c"))
+		})
+
+		It("should append missing parameter", func() {
+			u, _ := Parse("http://x/path#f")
+			u.QuerySet("q", "go&go")
+			Expect(u.String()).Should(Equal("http://x/path?q=go%26go#f"))
+		})
+	})
+
+	Describe("QueryEncoding", func() {
+		It("should use form encoding by default", func() {
+			u, _ := Parse("http://x/")
+			u.QuerySet("a", "b c")
+			Expect(u.Query).Should(Equal("a=b+c"))
+
+			u.SetQueryValues(url.Values{"a": {"b c"}})
+			Expect(u.Query).Should(Equal("a=b+c"))
+		})
+
+		It("should use RFC 3986 encoding", func() {
+			u, _ := Parse("http://x/")
+			u.QueryEncoding = RFC3986Encoding
+			u.QuerySet("a", "b c")
+			Expect(u.Query).Should(Equal("a=b%20c"))
+
+			u.SetQueryValues(url.Values{"a": {"b c+d"}})
+			Expect(u.Query).Should(Equal("a=b%20c%2Bd"))
+
+			u.SetQueryPairs([]QueryPair{{Key: "k k", Value: "v", HasEquals: true}})
+			Expect(u.Query).Should(Equal("k%20k=v"))
+			Expect(u.QueryValues()).Should(Equal(url.Values{"k k": {"v"}}))
+		})
+	})
+
+	Describe("SortQuery", func() {
+		It("should sort by key keeping duplicate keys order", func() {
+			u, _ := Parse("http://x/?b=2&a=1&a=0")
+			u.SortQuery()
+			Expect(u.Query).Should(Equal("a=1&a=0&b=2"))
+		})
+
+		It("should preserve encoding", func() {
+			u, _ := Parse("http://x/?z=go+language&%61=%20&y")
+			u.SortQuery()
+			Expect(u.Query).Should(Equal("%61=%20&y&z=go+language"))
+		})
+
+		It("should drop empty parameters", func() {
+			u, _ := Parse("http://x/?b=1&&a=1&")
+			u.SortQuery()
+			Expect(u.Query).Should(Equal("a=1&b=1"))
+		})
+	})
+
+	Describe("CanonicalQuery", func() {
+		It("should decode, sort and re-encode", func() {
+			u, _ := Parse("http://x/?b=%20&a=+")
+			Expect(u.CanonicalQuery()).Should(Equal("a=%20&b=%20"))
+		})
+
+		It("should sort duplicate keys by value", func() {
+			u, _ := Parse("http://x/?%61=2&a=1&b&c=x%2By")
+			Expect(u.CanonicalQuery()).Should(Equal("a=1&a=2&b&c=x%2By"))
+		})
+
+		It("should not depend on order of pairs with and without equals", func() {
+			for _, raw := range []string{"http://x/?a=&a", "http://x/?a&a="} {
+				u, _ := Parse(raw)
+				Expect(u.CanonicalQuery()).Should(Equal("a&a="), raw)
+			}
+		})
+
+		It("should reject malformed escapes", func() {
+			u, _ := Parse("http://x/?a=%zz")
+			_, err := u.CanonicalQuery()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package urlparser
+
+import "strings"
+
+// ResolveReference resolves the reference against the URL used as base,
+// RFC 3986 section 5.2.2, thus "#top" against "http://x/a?b=1" gives
+// "http://x/a?b=1#top". Neither the base nor the reference is modified.
+//
+// Without scheme and "//" the reference has no authority per RFC, so
+// its hier-part is taken as path, even if Parse put it in to Host
+// like for "g?y".
+func (u *URL) ResolveReference(ref *URL) *URL {
+	if ref.Scheme != "" || ref.DoubleSlash != "" {
+		target := ref.Clone()
+		if target.Scheme == "" {
+			target.Scheme = u.Scheme
+		}
+		if target.DoubleSlash != "" {
+			target.Path = RemoveDotSegments(target.Path)
+		}
+		return target
+	}
+
+	target := u.Clone()
+	target.Fragment = ref.Fragment
+	switch path := referencePath(ref); {
+	case path == "":
+		if ref.Query != "" || ref.ForceQuery {
+			target.Query = ref.Query
+			target.ForceQuery = ref.ForceQuery
+		}
+	case strings.HasPrefix(path, "/"):
+		target.Path = RemoveDotSegments(path)
+		target.Query = ref.Query
+		target.ForceQuery = ref.ForceQuery
+	default:
+		target.Path = RemoveDotSegments(mergePaths(u, path))
+		target.Query = ref.Query
+		target.ForceQuery = ref.ForceQuery
+	}
+	return target
+}
+
+// Resolve resolves the ref string against the base one and returns
+// the resulting URL string, e.g. "http://a/b/c/g" for "g" against
+// "http://a/b/c/d;p?q".
+func Resolve(base, ref string) (string, error) {
+	baseURL, err := Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+// referencePath returns the raw path of a reference without scheme and
+// authority: the whole hier-part, or the `./` prefixed primitive path.
+func referencePath(ref *URL) string {
+	if ref.Opaque != "" {
+		return ref.Opaque
+	}
+	return ref.Path
+}
+
+// mergePaths merges the relative path with the base path, RFC 3986 section 5.2.3.
+func mergePaths(base *URL, path string) string {
+	if base.BuildAuthority() != "" && base.Path == "" {
+		return "/" + path
+	}
+	return base.Path[:strings.LastIndex(base.Path, "/")+1] + path
+}
@@ -0,0 +1,125 @@
+package urlparser_test
+
+import (
+	"strings"
+
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolve", func() {
+	Describe("ResolveReference", func() {
+		It("should replace fragment only", func() {
+			base, _ := Parse("http://x/a?b=1")
+			ref, _ := Parse("#top")
+			resolved := base.ResolveReference(ref)
+			Expect(resolved.String()).Should(Equal("http://x/a?b=1#top"))
+			Expect(resolved.Scheme).Should(Equal("http"))
+			Expect(resolved.Host).Should(Equal("x"))
+			Expect(resolved.Path).Should(Equal("/a"))
+			Expect(resolved.Query).Should(Equal("b=1"))
+			Expect(resolved.Fragment).Should(Equal("top"))
+		})
+
+		It("should resolve relative references", func() {
+			base, _ := Parse("http://a/b/c/d;p?q#f")
+			for raw, expected := range map[string]string{
+				"":               "http://a/b/c/d;p?q",
+				"?y":             "http://a/b/c/d;p?y",
+				"?":              "http://a/b/c/d;p?",
+				"g?":             "http://a/b/c/g?",
+				"g":              "http://a/b/c/g",
+				"g?y#s":          "http://a/b/c/g?y#s",
+				"../g":           "http://a/b/g",
+				"/g":             "http://a/g",
+				"//g/x":          "http://g/x",
+				"index.php?t=1":  "http://a/b/c/index.php?t=1",
+				"https://h/./p":  "https://h/p",
+				"mailto:a@b.com": "mailto:a@b.com",
+			} {
+				ref, _ := Parse(raw)
+				Expect(base.ResolveReference(ref).String()).Should(Equal(expected), raw)
+			}
+		})
+
+		It("should merge with empty base path", func() {
+			base, _ := Parse("http://a")
+			ref, _ := Parse("g")
+			Expect(base.ResolveReference(ref).String()).Should(Equal("http://a/g"))
+		})
+
+		It("should not mutate base", func() {
+			base, _ := Parse("http://x/a?b=1")
+			ref, _ := Parse("c?d=2#e")
+			base.ResolveReference(ref)
+			Expect(base.String()).Should(Equal("http://x/a?b=1"))
+		})
+	})
+
+	Describe("Resolve", func() {
+		base := "http://a/b/c/d;p?q"
+
+		It("should resolve RFC 3986 normal examples", func() {
+			for ref, expected := range map[string]string{
+				"g:h":     "g:h",
+				"g":       "http://a/b/c/g",
+				"./g":     "http://a/b/c/g",
+				"g/":      "http://a/b/c/g/",
+				"/g":      "http://a/g",
+				"//g":     "http://g",
+				"?y":      "http://a/b/c/d;p?y",
+				"g?y":     "http://a/b/c/g?y",
+				"#s":      "http://a/b/c/d;p?q#s",
+				"g#s":     "http://a/b/c/g#s",
+				"g?y#s":   "http://a/b/c/g?y#s",
+				";x":      "http://a/b/c/;x",
+				"g;x":     "http://a/b/c/g;x",
+				"g;x?y#s": "http://a/b/c/g;x?y#s",
+				"":        "http://a/b/c/d;p?q",
+				".":       "http://a/b/c/",
+				"./":      "http://a/b/c/",
+				"..":      "http://a/b/",
+				"../":     "http://a/b/",
+				"../g":    "http://a/b/g",
+				"../..":   "http://a/",
+				"../../":  "http://a/",
+				"../../g": "http://a/g",
+			} {
+				Expect(Resolve(base, ref)).Should(Equal(expected), ref)
+			}
+		})
+
+		It("should resolve RFC 3986 abnormal examples", func() {
+			for ref, expected := range map[string]string{
+				"../../../g":    "http://a/g",
+				"../../../../g": "http://a/g",
+				"/./g":          "http://a/g",
+				"/../g":         "http://a/g",
+				"g.":            "http://a/b/c/g.",
+				".g":            "http://a/b/c/.g",
+				"g..":           "http://a/b/c/g..",
+				"..g":           "http://a/b/c/..g",
+				"./../g":        "http://a/b/g",
+				"./g/.":         "http://a/b/c/g/",
+				"g/./h":         "http://a/b/c/g/h",
+				"g/../h":        "http://a/b/c/h",
+				"g;x=1/./y":     "http://a/b/c/g;x=1/y",
+				"g;x=1/../y":    "http://a/b/c/y",
+				"g?y/./x":       "http://a/b/c/g?y/./x",
+				"g?y/../x":      "http://a/b/c/g?y/../x",
+				"g#s/./x":       "http://a/b/c/g#s/./x",
+				"g#s/../x":      "http://a/b/c/g#s/../x",
+				"http:g":        "http:g",
+			} {
+				Expect(Resolve(base, ref)).Should(Equal(expected), ref)
+			}
+		})
+
+		It("should return parse error", func() {
+			_, err := Resolve(base, "g/"+strings.Repeat("a", DefaultMaxURLLength))
+			Expect(err).Should(Equal(ErrURLTooLong))
+		})
+	})
+})
@@ -0,0 +1,335 @@
+package urlparser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// schemes maps registered schemes to whether they are hierarchical,
+// i.e. followed by "//" authority, or opaque like "mailto:user@host".
+// It's guarded by schemesMu.
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]bool{
+		"android-app": true,
+		"chrome":      true,
+		"coap":        true,
+		"coaps":       true,
+		"file":        true,
+		"ftp":         true,
+		"gopher":      true,
+		"http":        true,
+		"https":       true,
+		"ldap":        true,
+		"ldaps":       true,
+		"mongodb":     true,
+		"redis":       true,
+		"rtsp":        true,
+		"rtsps":       true,
+		"sftp":        true,
+		"telnet":      true,
+		"ws":          true,
+		"wss":         true,
+
+		"about":       false,
+		"blob":        false,
+		"data":        false,
+		"fax":         false,
+		"jar":         false,
+		"javascript":  false,
+		"magnet":      false,
+		"mailto":      false,
+		"news":        false,
+		"sip":         false,
+		"sips":        false,
+		"sms":         false,
+		"tel":         false,
+		"urn":         false,
+		"vbscript":    false,
+		"view-source": false,
+	}
+)
+
+// RegisterScheme registers a custom scheme. Parse expects an authority
+// after "//" for hierarchical schemes, while the rest of opaque ones
+// is kept in Opaque, e.g. "myapp-cmd:open". It's safe for concurrent use.
+func RegisterScheme(name string, hierarchical bool) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[strings.ToLower(name)] = hierarchical
+}
+
+// lookupScheme reports whether the scheme is hierarchical and registered.
+func lookupScheme(name string) (hierarchical, ok bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	hierarchical, ok = schemes[strings.ToLower(name)]
+	return hierarchical, ok
+}
+
+// isOpaqueScheme reports whether the scheme is registered as opaque.
+func isOpaqueScheme(name string) bool {
+	hierarchical, ok := lookupScheme(name)
+	return ok && !hierarchical
+}
+
+// dangerousSchemes can run code or read local files when followed as links.
+var dangerousSchemes = map[string]bool{
+	"data":       true,
+	"file":       true,
+	"javascript": true,
+	"vbscript":   true,
+}
+
+// safeSchemes is the allowlist of dangerous schemes which are accepted
+// anyway, see SetSafeSchemes. It's guarded by safeSchemesMu.
+var (
+	safeSchemesMu sync.RWMutex
+	safeSchemes   = map[string]bool{}
+)
+
+// SetSafeSchemes replaces the allowlist of schemes never reported by
+// IsPotentiallyDangerousScheme, e.g. []string{"data"} to accept inline
+// images. It's safe for concurrent use.
+func SetSafeSchemes(names []string) {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[strings.ToLower(name)] = true
+	}
+
+	safeSchemesMu.Lock()
+	defer safeSchemesMu.Unlock()
+	safeSchemes = allowed
+}
+
+// IsPotentiallyDangerousScheme reports whether the URL has javascript:,
+// data:, vbscript: or file: scheme not allowed by SetSafeSchemes.
+// Tabs and newlines inside the scheme are ignored like browsers do,
+// so "java\tscript:alert(1)" is dangerous too.
+func (u *URL) IsPotentiallyDangerousScheme() bool {
+	scheme := strings.ToLower(tabsAndNewlinesReplacer.Replace(u.Scheme))
+	if !dangerousSchemes[scheme] {
+		return false
+	}
+
+	safeSchemesMu.RLock()
+	defer safeSchemesMu.RUnlock()
+	return !safeSchemes[scheme]
+}
+
+// IsWebSocket reports whether the URL has ws: or wss: scheme.
+func (u *URL) IsWebSocket() bool {
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "ws" || scheme == "wss"
+}
+
+// compositeSchemes are browser-style schemes wrapping another URL.
+var compositeSchemes = map[string]bool{
+	"jar":         true,
+	"view-source": true,
+}
+
+// jarSeparator separates the archive URL from the entry path in jar: URLs.
+const jarSeparator = "!/"
+
+// innerRaw returns the raw part following the "scheme:" prefix.
+func (u *URL) innerRaw() string {
+	inner := u.Opaque
+	if u.Query != "" || u.ForceQuery {
+		inner += "?" + u.Query
+	}
+	if u.Fragment != "" {
+		inner += "#" + u.Fragment
+	}
+	return inner
+}
+
+// InnerURL returns the URL wrapped by a composite scheme such as
+// "view-source:https://x/" or "jar:http://host/a.jar!/b/c".
+// For jar: URLs it's the archive URL, see JarEntry for the entry path.
+func (u *URL) InnerURL() (*URL, error) {
+	scheme := strings.ToLower(u.Scheme)
+	if !compositeSchemes[scheme] {
+		return nil, fmt.Errorf("urlparser: %q is not a composite scheme", u.Scheme)
+	}
+
+	inner := u.innerRaw()
+	if scheme == "jar" {
+		if i := strings.Index(inner, jarSeparator); i != -1 {
+			inner = inner[:i]
+		}
+	}
+
+	return Parse(inner)
+}
+
+// JarEntry returns the entry path of a jar: URL, e.g. "/b/c" for
+// "jar:http://host/a.jar!/b/c".
+func (u *URL) JarEntry() (string, error) {
+	if strings.ToLower(u.Scheme) != "jar" {
+		return "", fmt.Errorf("urlparser: %q is not a jar scheme", u.Scheme)
+	}
+
+	inner := u.innerRaw()
+	i := strings.Index(inner, jarSeparator)
+	if i == -1 {
+		return "", fmt.Errorf("urlparser: missing %q in jar url", jarSeparator)
+	}
+	return inner[i+1:], nil
+}
+
+// BlobOrigin parses the URL wrapped by a blob: URL, e.g. for
+// "blob:https://example.com/550e8400-e29b" it's "https://example.com/550e8400-e29b".
+func (u *URL) BlobOrigin() (*URL, error) {
+	if strings.ToLower(u.Scheme) != "blob" {
+		return nil, fmt.Errorf("urlparser: %q is not a blob scheme", u.Scheme)
+	}
+	return Parse(u.innerRaw())
+}
+
+// SIPAddress returns the user and the host of a sip: or sips: URI,
+// e.g. "alice" and "example.com" for "sip:alice@example.com;transport=tcp".
+func (u *URL) SIPAddress() (user, host string, err error) {
+	if scheme := strings.ToLower(u.Scheme); scheme != "sip" && scheme != "sips" {
+		return "", "", fmt.Errorf("urlparser: %q is not a sip scheme", u.Scheme)
+	}
+
+	// uri-parameters follow the host after `;`
+	address := u.Opaque
+	if i := strings.Index(address, ";"); i != -1 {
+		address = address[:i]
+	}
+	userinfo, host, _ := splitUserinfoHostPortFromAuthority(address)
+	return userinfo.Username, host, nil
+}
+
+// MailtoAddresses returns the decoded recipients of a mailto: URL,
+// RFC 6068: comma-separated addresses before "?" followed by those
+// of "to" header fields, e.g. "a@b.com" and "c@d.com" for
+// "mailto:a@b.com?to=c@d.com&subject=hi". The leading slash of
+// "mailto:/a@b.com" is not a part of the address. Every address is roughly
+// validated to have non-empty local and domain parts around "@".
+func (u *URL) MailtoAddresses() ([]string, error) {
+	if strings.ToLower(u.Scheme) != "mailto" {
+		return nil, fmt.Errorf("urlparser: %q is not a mailto scheme", u.Scheme)
+	}
+
+	// the recipients before "?" are percent-encoded, while the "to"
+	// values are already decoded by QueryValues
+	recipients := []string{}
+	if opaque := strings.TrimPrefix(u.Opaque, "/"); opaque != "" {
+		for _, address := range strings.Split(opaque, ",") {
+			decoded, err := url.PathUnescape(address)
+			if err != nil {
+				return nil, err
+			}
+			recipients = append(recipients, decoded)
+		}
+	}
+	for _, to := range u.QueryValues()["to"] {
+		recipients = append(recipients, strings.Split(to, ",")...)
+	}
+
+	addresses := []string{}
+	for _, recipient := range recipients {
+		if recipient = strings.TrimSpace(recipient); recipient == "" {
+			continue
+		}
+		if err := validateEmail(recipient); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, recipient)
+	}
+	return addresses, nil
+}
+
+// Email returns the first address of a mailto: URL, see MailtoAddresses.
+func (u *URL) Email() (string, error) {
+	addresses, err := u.MailtoAddresses()
+	if err != nil {
+		return "", err
+	}
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("urlparser: no address in mailto url")
+	}
+	return addresses[0], nil
+}
+
+func validateEmail(address string) error {
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return fmt.Errorf("urlparser: invalid email address %q", address)
+	}
+	return nil
+}
+
+// AndroidAppComponents splits an android-app: URL of the form
+// "android-app://package[/scheme[/host[/path]]]", e.g. "com.example",
+// "https", "host" and "/path" for "android-app://com.example/https/host/path".
+// Missing components are empty.
+func (u *URL) AndroidAppComponents() (pkg, scheme, host, path string, err error) {
+	if strings.ToLower(u.Scheme) != "android-app" {
+		return "", "", "", "", fmt.Errorf("urlparser: %q is not an android-app scheme", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", "", "", "", fmt.Errorf("urlparser: missing package name in android-app url")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 3)
+	scheme = parts[0]
+	if len(parts) > 1 {
+		host = parts[1]
+	}
+	if len(parts) > 2 {
+		path = "/" + parts[2]
+	}
+	return u.Host, scheme, host, path, nil
+}
+
+// MagnetParams returns the decoded parameters of a magnet: link like
+// "magnet:?xt=urn:btih:HASH&dn=name&tr=tracker1&tr=tracker2", repeated
+// xt and tr keep all the values. It's nil for other schemes.
+// Validate rejects magnet links with authority.
+func (u *URL) MagnetParams() url.Values {
+	if !u.isMagnet() {
+		return nil
+	}
+	return u.QueryValues()
+}
+
+func (u *URL) isMagnet() bool {
+	return strings.ToLower(u.Scheme) == "magnet"
+}
+
+// ftpTypecode precedes the transfer type at the end of ftp path, RFC 1738.
+const ftpTypecode = ";type="
+
+// FTPTypecode returns the transfer type of an ftp: URL, e.g. "i" for
+// "ftp://host/dir/file;type=i". Non-ftp schemes and paths without
+// a valid ("a", "i" or "d") typecode report false.
+func (u *URL) FTPTypecode() (string, bool) {
+	if strings.ToLower(u.Scheme) != "ftp" {
+		return "", false
+	}
+
+	i := strings.LastIndex(u.Path, ftpTypecode)
+	if i == -1 {
+		return "", false
+	}
+	switch typecode := strings.ToLower(u.Path[i+len(ftpTypecode):]); typecode {
+	case "a", "i", "d":
+		return typecode, true
+	}
+	return "", false
+}
+
+// FTPPath returns the path of an ftp: URL without the typecode,
+// e.g. "/dir/file" for "ftp://host/dir/file;type=i".
+func (u *URL) FTPPath() string {
+	if _, ok := u.FTPTypecode(); ok {
+		return u.Path[:strings.LastIndex(u.Path, ftpTypecode)]
+	}
+	return u.Path
+}
@@ -0,0 +1,375 @@
+package urlparser_test
+
+import (
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schemes", func() {
+	Describe("RegisterScheme", func() {
+		It("should parse registered hierarchical scheme", func() {
+			RegisterScheme("myapp", true)
+			url, _ := Parse("myapp://action/path?x=1")
+			Expect(url.Scheme).Should(Equal("myapp"))
+			Expect(url.DoubleSlash).Should(Equal("//"))
+			Expect(url.Host).Should(Equal("action"))
+			Expect(url.Path).Should(Equal("/path"))
+			Expect(url.Query).Should(Equal("x=1"))
+		})
+
+		It("should parse registered opaque scheme", func() {
+			RegisterScheme("myapp-cmd", false)
+			url, _ := Parse("myapp-cmd:open@file?x=1")
+			Expect(url.Scheme).Should(Equal("myapp-cmd"))
+			Expect(url.Opaque).Should(Equal("open@file"))
+			Expect(url.Authority).Should(Equal(""))
+			Expect(url.Host).Should(Equal(""))
+			Expect(url.Path).Should(Equal(""))
+			Expect(url.Query).Should(Equal("x=1"))
+			Expect(url.String()).Should(Equal("myapp-cmd:open@file?x=1"))
+		})
+
+		It("should not treat numeric body of opaque scheme as port", func() {
+			RegisterScheme("myapp-cmd", false)
+			url, _ := Parse("myapp-cmd:8080")
+			Expect(url.Scheme).Should(Equal("myapp-cmd"))
+			Expect(url.Opaque).Should(Equal("8080"))
+			Expect(url.Port).Should(Equal(""))
+		})
+	})
+
+	Describe("Browser pseudo-schemes", func() {
+		It("should handle about: url", func() {
+			url, err := Parse("about:blank")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("about"))
+			Expect(url.Opaque).Should(Equal("blank"))
+			Expect(url.Host).Should(Equal(""))
+			Expect(url.String()).Should(Equal("about:blank"))
+		})
+
+		It("should handle chrome: url", func() {
+			url, err := Parse("chrome://settings/")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("chrome"))
+			Expect(url.Host).Should(Equal("settings"))
+			Expect(url.Path).Should(Equal("/"))
+			Expect(url.String()).Should(Equal("chrome://settings/"))
+		})
+
+		It("should not treat numeric about: body as port", func() {
+			url, _ := Parse("about:8080")
+			Expect(url.Scheme).Should(Equal("about"))
+			Expect(url.Opaque).Should(Equal("8080"))
+		})
+	})
+
+	Describe("InnerURL", func() {
+		It("should handle jar: url", func() {
+			url, _ := Parse("jar:http://host/a.jar!/b/c")
+			Expect(url.Scheme).Should(Equal("jar"))
+
+			inner, err := url.InnerURL()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(inner.Scheme).Should(Equal("http"))
+			Expect(inner.Host).Should(Equal("host"))
+			Expect(inner.Path).Should(Equal("/a.jar"))
+
+			entry, err := url.JarEntry()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entry).Should(Equal("/b/c"))
+			Expect(url.Opaque).Should(Equal("http://host/a.jar!/b/c"))
+			Expect(url.String()).Should(Equal("jar:http://host/a.jar!/b/c"))
+		})
+
+		It("should handle view-source: url", func() {
+			url, _ := Parse("view-source:https://x/?q=1#top")
+			Expect(url.Scheme).Should(Equal("view-source"))
+
+			inner, err := url.InnerURL()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(inner.Scheme).Should(Equal("https"))
+			Expect(inner.Host).Should(Equal("x"))
+			Expect(inner.Path).Should(Equal("/"))
+			Expect(inner.Query).Should(Equal("q=1"))
+			Expect(inner.Fragment).Should(Equal("top"))
+			Expect(url.Host).Should(Equal(""))
+			Expect(url.String()).Should(Equal("view-source:https://x/?q=1#top"))
+		})
+
+		It("should fail for non-composite scheme", func() {
+			url, _ := Parse("http://host/a.jar!/b/c")
+			_, err := url.InnerURL()
+			Expect(err).Should(HaveOccurred())
+
+			_, err = url.JarEntry()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("BlobOrigin", func() {
+		It("should parse blob: url origin", func() {
+			url, _ := Parse("blob:https://example.com/550e8400-e29b")
+			Expect(url.Scheme).Should(Equal("blob"))
+			Expect(url.Opaque).Should(Equal("https://example.com/550e8400-e29b"))
+
+			origin, err := url.BlobOrigin()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(origin.Scheme).Should(Equal("https"))
+			Expect(origin.Host).Should(Equal("example.com"))
+			Expect(origin.Path).Should(Equal("/550e8400-e29b"))
+			Expect(origin.Origin()).Should(Equal("https://example.com:443"))
+		})
+
+		It("should fail for non-blob scheme", func() {
+			url, _ := Parse("https://example.com/550e8400-e29b")
+			_, err := url.BlobOrigin()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("RTSP and SIP", func() {
+		It("should handle rtsp: url", func() {
+			url, _ := Parse("rtsp://cam:554/stream")
+			Expect(url.Scheme).Should(Equal("rtsp"))
+			Expect(url.Host).Should(Equal("cam"))
+			Expect(url.Port).Should(Equal("554"))
+			Expect(url.Path).Should(Equal("/stream"))
+
+			port, _ := DefaultPort("rtsps")
+			Expect(port).Should(Equal(322))
+		})
+
+		It("should handle sip: url as opaque", func() {
+			url, _ := Parse("sip:alice@example.com")
+			Expect(url.Scheme).Should(Equal("sip"))
+			Expect(url.Opaque).Should(Equal("alice@example.com"))
+			Expect(url.Host).Should(Equal(""))
+			Expect(url.String()).Should(Equal("sip:alice@example.com"))
+
+			port, _ := DefaultPort("sip")
+			Expect(port).Should(Equal(5060))
+			port, _ = DefaultPort("sips")
+			Expect(port).Should(Equal(5061))
+		})
+
+		It("should return sip address", func() {
+			url, _ := Parse("sip:alice@example.com")
+			user, host, err := url.SIPAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(user).Should(Equal("alice"))
+			Expect(host).Should(Equal("example.com"))
+
+			url, _ = Parse("sips:bob:secret@pbx.example.com:5061;transport=tls?subject=hi")
+			user, host, err = url.SIPAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(user).Should(Equal("bob"))
+			Expect(host).Should(Equal("pbx.example.com"))
+		})
+
+		It("should fail to return sip address of non-sip scheme", func() {
+			url, _ := Parse("mailto:alice@example.com")
+			_, _, err := url.SIPAddress()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("Email", func() {
+		It("should return the address", func() {
+			url, _ := Parse("mailto:a@b.com")
+			Expect(url.Email()).Should(Equal("a@b.com"))
+
+			url, _ = Parse("mailto:first%20last%40x@b.com?subject=hi")
+			Expect(url.Email()).Should(Equal("first last@x@b.com"))
+		})
+
+		It("should return the first of many addresses", func() {
+			url, _ := Parse("mailto:a@b.com,c@d.com?to=e@f.com&cc=g@h.com")
+			Expect(url.Email()).Should(Equal("a@b.com"))
+			Expect(url.MailtoAddresses()).Should(Equal([]string{"a@b.com", "c@d.com", "e@f.com"}))
+
+			url, _ = Parse("mailto:?to=e@f.com")
+			Expect(url.Email()).Should(Equal("e@f.com"))
+		})
+
+		It("should decode to field once", func() {
+			url, _ := Parse("mailto:?to=100%25@x.com")
+			Expect(url.Email()).Should(Equal("100%@x.com"))
+
+			url, _ = Parse("mailto:?to=a%2525@x.com")
+			Expect(url.Email()).Should(Equal("a%25@x.com"))
+		})
+
+		It("should drop leading slash", func() {
+			url, _ := Parse("mailto:/a@b.com")
+			Expect(url.Email()).Should(Equal("a@b.com"))
+		})
+
+		It("should reject invalid address", func() {
+			for _, raw := range []string{"mailto:bad", "mailto:@b.com", "mailto:a@", "mailto:", "mailto:a@b.com,bad", "http://a@b.com"} {
+				url, _ := Parse(raw)
+				_, err := url.Email()
+				Expect(err).Should(HaveOccurred(), raw)
+			}
+		})
+	})
+
+	Describe("Deep links", func() {
+		It("should return android-app components", func() {
+			url, err := Parse("android-app://com.example/https/host/path?x=1")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("android-app"))
+			Expect(url.Host).Should(Equal("com.example"))
+
+			pkg, scheme, host, path, err := url.AndroidAppComponents()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pkg).Should(Equal("com.example"))
+			Expect(scheme).Should(Equal("https"))
+			Expect(host).Should(Equal("host"))
+			Expect(path).Should(Equal("/path"))
+		})
+
+		It("should return android-app package only", func() {
+			url, _ := Parse("android-app://com.example.app")
+			pkg, scheme, host, path, err := url.AndroidAppComponents()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pkg).Should(Equal("com.example.app"))
+			Expect(scheme).Should(Equal(""))
+			Expect(host).Should(Equal(""))
+			Expect(path).Should(Equal(""))
+		})
+
+		It("should fail for other schemes", func() {
+			url, _ := Parse("myapp://action?x=1")
+			_, _, _, _, err := url.AndroidAppComponents()
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should parse custom deep link", func() {
+			url, err := Parse("my-app://action?x=1")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("my-app"))
+			Expect(url.Host).Should(Equal("action"))
+			Expect(url.Query).Should(Equal("x=1"))
+			Expect(url.String()).Should(Equal("my-app://action?x=1"))
+		})
+	})
+
+	Describe("IsPotentiallyDangerousScheme", func() {
+		It("should detect dangerous schemes", func() {
+			for raw, expected := range map[string]bool{
+				"javascript:alert(1)":                 true,
+				"JavaScript:alert(1)":                 true,
+				"java\tscript:alert(1)":               true,
+				"vbscript:msgbox(1)":                  true,
+				"data:text/html;base64,PHNjcg":        true,
+				"file:///etc/passwd":                  true,
+				"javascript:1/alert(document.cookie)": true,
+				"JavaScript:1/alert(1)":               true,
+				"java\tscript:1/alert(1)":             true,
+				"vbscript:1/msgbox(1)":                true,
+				"javascript:0":                        true,
+				"data:1/x":                            true,
+				"https://x":                           false,
+				"mailto:a@b.com":                      false,
+				"/javascript:alert(1)":                false,
+				"page.html":                           false,
+			} {
+				url, _ := Parse(raw)
+				Expect(url.IsPotentiallyDangerousScheme()).Should(Equal(expected), raw)
+			}
+		})
+
+		It("should accept allowed schemes", func() {
+			SetSafeSchemes([]string{"DATA"})
+			defer SetSafeSchemes(nil)
+			url, _ := Parse("data:image/png;base64,iVBORw0KGgo")
+			Expect(url.IsPotentiallyDangerousScheme()).Should(BeFalse())
+
+			url, _ = Parse("javascript:alert(1)")
+			Expect(url.IsPotentiallyDangerousScheme()).Should(BeTrue())
+		})
+	})
+
+	Describe("MagnetParams", func() {
+		It("should return params of multi-tracker magnet link", func() {
+			url, err := Parse("magnet:?xt=urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a&dn=Some+Name&tr=udp%3A%2F%2Ftracker.one%3A80&tr=udp%3A%2F%2Ftracker.two%3A6969")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Scheme).Should(Equal("magnet"))
+			Expect(url.Validate()).Should(Succeed())
+
+			params := url.MagnetParams()
+			Expect(params["xt"]).Should(Equal([]string{"urn:btih:c12fe1c06bba254a9dc9f519b335aa7c1367a88a"}))
+			Expect(params.Get("dn")).Should(Equal("Some Name"))
+			Expect(params["tr"]).Should(Equal([]string{"udp://tracker.one:80", "udp://tracker.two:6969"}))
+		})
+
+		It("should return nil for other schemes", func() {
+			url, _ := Parse("http://x/?xt=urn:btih:HASH")
+			Expect(url.MagnetParams()).Should(BeNil())
+		})
+
+		It("should reject magnet link with authority", func() {
+			url, _ := Parse("magnet://tracker.one/?xt=urn:btih:HASH")
+			Expect(url.Validate()).ShouldNot(Succeed())
+		})
+	})
+
+	Describe("WebSocket", func() {
+		It("should handle ws: url", func() {
+			url, _ := Parse("ws://host/path")
+			Expect(url.IsWebSocket()).Should(BeTrue())
+			Expect(url.Host).Should(Equal("host"))
+			Expect(url.Path).Should(Equal("/path"))
+			Expect(url.Origin()).Should(Equal("ws://host:80"))
+			Expect(url.Validate()).Should(Succeed())
+		})
+
+		It("should handle wss: url", func() {
+			url, _ := Parse("wss://host:443/chat?token=x")
+			Expect(url.IsWebSocket()).Should(BeTrue())
+			Expect(url.HasDefaultPort()).Should(BeTrue())
+			Expect(url.Query).Should(Equal("token=x"))
+		})
+
+		It("should flag fragment in WebSocket url as invalid", func() {
+			url, _ := Parse("wss://host/chat#frag")
+			err := url.Validate()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("WebSocket"))
+		})
+
+		It("should not treat http as WebSocket", func() {
+			url, _ := Parse("http://host/path")
+			Expect(url.IsWebSocket()).Should(BeFalse())
+		})
+	})
+
+	Describe("FTPTypecode", func() {
+		It("should extract typecode from ftp path", func() {
+			url, _ := Parse("ftp://host/f;type=a")
+			typecode, ok := url.FTPTypecode()
+			Expect(ok).Should(BeTrue())
+			Expect(typecode).Should(Equal("a"))
+			Expect(url.FTPPath()).Should(Equal("/f"))
+			Expect(url.Path).Should(Equal("/f;type=a"))
+
+			url, _ = Parse("ftp://host/dir/file;type=I")
+			typecode, ok = url.FTPTypecode()
+			Expect(ok).Should(BeTrue())
+			Expect(typecode).Should(Equal("i"))
+			Expect(url.FTPPath()).Should(Equal("/dir/file"))
+		})
+
+		It("should report false without valid typecode", func() {
+			for _, raw := range []string{"ftp://host/f", "ftp://host/f;type=x", "http://host/f;type=a"} {
+				url, _ := Parse(raw)
+				_, ok := url.FTPTypecode()
+				Expect(ok).Should(BeFalse(), raw)
+				Expect(url.FTPPath()).Should(Equal(url.Path))
+			}
+		})
+	})
+})
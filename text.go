@@ -0,0 +1,107 @@
+package urlparser
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNoURL is returned by ParsePrefix when the text doesn't start with a URL.
+var ErrNoURL = errors.New("urlparser: no url at the start of text")
+
+// trailingPunctuation is not considered a part of a URL embedded in text
+// when it ends the URL, as in "see http://x/y."
+const trailingPunctuation = ".,;:!?"
+
+// ParsePrefix parses the URL at the start of s and returns the rest
+// of the text. The URL ends at whitespace, a quote, or a closing bracket
+// without a matching opening one inside the URL; trailing punctuation
+// is left in rest, thus "http://x/y. end" gives rest ". end".
+func ParsePrefix(s string) (u *URL, rest string, err error) {
+	end := prefixEnd(s)
+	end = len(strings.TrimRight(s[:end], trailingPunctuation))
+	if end == 0 {
+		return nil, s, ErrNoURL
+	}
+
+	u, err = Parse(s[:end])
+	if err != nil {
+		return nil, s, err
+	}
+	return u, s[end:], nil
+}
+
+// FindURLs returns all http and https URLs found in text. A trailing
+// period or an unmatched closing paren is not included in the URL.
+// The URL must start at a word boundary, so "foohttp://x" is skipped.
+func FindURLs(text string) []*URL {
+	urls := []*URL{}
+	for i := 0; i < len(text); {
+		start := nextURLStart(text, i)
+		if start < 0 {
+			break
+		}
+
+		u, rest, err := ParsePrefix(text[start:])
+		if err != nil || u.Host == "" {
+			// skip the whole rejected span, rescanning it byte by byte is quadratic
+			i = start + prefixEnd(text[start:])
+			continue
+		}
+		urls = append(urls, u)
+		i = len(text) - len(rest)
+	}
+	return urls
+}
+
+// urlPrefixes are the case-insensitive prefixes looked up by FindURLs.
+var urlPrefixes = []string{"http://", "https://"}
+
+// nextURLStart returns the index of the next case-insensitive "http://"
+// or "https://" in text at or after from, which doesn't follow a letter
+// or a digit, or -1.
+func nextURLStart(text string, from int) int {
+	for i := from; i < len(text); i++ {
+		if text[i] != 'h' && text[i] != 'H' || i > 0 && (isAlpha(text[i-1]) || isDigit(text[i-1])) {
+			continue
+		}
+		for _, prefix := range urlPrefixes {
+			if len(text)-i >= len(prefix) && strings.EqualFold(text[i:i+len(prefix)], prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// prefixEnd returns the index where the URL at the start of s ends.
+// The scan stops right after DefaultMaxURLLength, since Parse rejects
+// longer URLs anyway.
+func prefixEnd(s string) int {
+	limit := len(s)
+	if limit > DefaultMaxURLLength+1 {
+		limit = DefaultMaxURLLength + 1
+	}
+
+	parens, brackets := 0, 0
+	for i := 0; i < limit; i++ {
+		switch s[i] {
+		case ' ', '\t', '\r', '\n', '\f', '"', '\'', '`', '<', '>', '{', '}':
+			return i
+		case '(':
+			parens++
+		case ')':
+			if parens == 0 {
+				return i
+			}
+			parens--
+		case '[':
+			brackets++
+		case ']':
+			if brackets == 0 {
+				return i
+			}
+			brackets--
+		}
+	}
+	return limit
+}
@@ -0,0 +1,104 @@
+package urlparser_test
+
+import (
+	"strings"
+
+	. "github.com/pavlik/urlparser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Text", func() {
+	Describe("ParsePrefix", func() {
+		It("should leave trailing punctuation in rest", func() {
+			url, rest, err := ParsePrefix("http://x/y. end")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.String()).Should(Equal("http://x/y"))
+			Expect(rest).Should(Equal(". end"))
+		})
+
+		It("should stop at whitespace and quotes", func() {
+			url, rest, err := ParsePrefix("http://x/y?a=1 for details")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Query).Should(Equal("a=1"))
+			Expect(rest).Should(Equal(" for details"))
+
+			url, rest, err = ParsePrefix(`http://x/y">link`)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Path).Should(Equal("/y"))
+			Expect(rest).Should(Equal(`">link`))
+		})
+
+		It("should stop at unmatched closing bracket", func() {
+			url, rest, err := ParsePrefix("http://x/y) or")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Path).Should(Equal("/y"))
+			Expect(rest).Should(Equal(") or"))
+
+			url, rest, err = ParsePrefix("http://en.wikipedia.org/wiki/Go_(language))")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Path).Should(Equal("/wiki/Go_(language)"))
+			Expect(rest).Should(Equal(")"))
+
+			url, rest, err = ParsePrefix("http://[::1]:8080/]")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(url.Host).Should(Equal("::1"))
+			Expect(rest).Should(Equal("]"))
+		})
+
+		It("should fail without url", func() {
+			_, rest, err := ParsePrefix(" text")
+			Expect(err).Should(Equal(ErrNoURL))
+			Expect(rest).Should(Equal(" text"))
+		})
+	})
+
+	Describe("FindURLs", func() {
+		It("should find urls in free text", func() {
+			urls := FindURLs("Docs at https://golang.org/doc. See also (http://x.com/a?b=1) and HTTP://Y.COM/!")
+			strs := []string{}
+			for _, url := range urls {
+				strs = append(strs, url.String())
+			}
+			Expect(strs).Should(Equal([]string{
+				"https://golang.org/doc",
+				"http://x.com/a?b=1",
+				"HTTP://Y.COM/",
+			}))
+		})
+
+		It("should keep balanced parens", func() {
+			urls := FindURLs("(see http://en.wikipedia.org/wiki/Go_(language))")
+			Expect(urls).Should(HaveLen(1))
+			Expect(urls[0].Path).Should(Equal("/wiki/Go_(language)"))
+		})
+
+		It("should skip non-http urls and bare prefixes", func() {
+			Expect(FindURLs("ftp://x.com/ and mailto:a@b.com or http:// alone")).Should(BeEmpty())
+		})
+
+		It("should require word boundary before url", func() {
+			for _, text := range []string{"foohttp://evil.com", "mailto:xhttps://e.com", "1http://x.com"} {
+				Expect(FindURLs(text)).Should(BeEmpty(), text)
+			}
+
+			urls := FindURLs("x:http://a.com/ and-https://b.com/")
+			Expect(urls).Should(HaveLen(2))
+			Expect(urls[1].Host).Should(Equal("b.com"))
+		})
+
+		It("should keep offsets on text with invalid UTF-8 and non-ASCII", func() {
+			for _, text := range []string{"\xff http://example.com/x", "İ http://example.com/x"} {
+				urls := FindURLs(text)
+				Expect(urls).Should(HaveLen(1), text)
+				Expect(urls[0].String()).Should(Equal("http://example.com/x"), text)
+			}
+		})
+
+		It("should skip rejected spans in linear time", func() {
+			Expect(FindURLs(strings.Repeat("http:///", 150000))).Should(BeEmpty())
+			Expect(FindURLs(strings.Repeat("http:/// ", 100000) + "http://x.com/")).Should(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,181 @@
+package urlparser
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks every populated component of the URL against the
+// character sets allowed by RFC 3986 and returns the first violation.
+// Parse is lenient and accepts input that Validate may reject.
+func (u *URL) Validate() error {
+	if u.Scheme != "" {
+		if err := validateScheme(u.Scheme); err != nil {
+			return err
+		}
+	}
+	if u.User != nil {
+		if err := validateChars("username", u.User.Username, isUserinfoChar); err != nil {
+			return err
+		}
+		if err := validateChars("password", u.User.Password, isUserinfoChar); err != nil {
+			return err
+		}
+	}
+	if u.Host != "" {
+		if err := validateHost(u.Host); err != nil {
+			return err
+		}
+	} else if u.HasCredentials() {
+		// `http://user@` has userinfo but no host
+		return fmt.Errorf("urlparser: userinfo without host")
+	}
+	if u.authorityUnchanged() {
+		// the host and the port lost a part of the raw authority, e.g. `x:8a`,
+		// the empty port of `x:` is allowed by RFC 3986
+		hostPort := u.Authority[strings.LastIndex(u.Authority, "@")+1:]
+		if strings.TrimSuffix(hostPort, ":") != u.hostPort() {
+			return fmt.Errorf("urlparser: invalid authority %q", u.Authority)
+		}
+	}
+	if err := validateChars("port", u.Port, isDigit); err != nil {
+		return err
+	}
+	if err := validateChars("path", u.Path, isPathChar); err != nil {
+		return err
+	}
+	if u.HasOverlongEncoding() {
+		return fmt.Errorf("urlparser: invalid UTF-8 percent-encoding in path %q", u.Path)
+	}
+	if err := validateChars("query", u.Query, isQueryChar); err != nil {
+		return err
+	}
+	if u.isMagnet() && (u.DoubleSlash != "" || u.Authority != "") {
+		return fmt.Errorf("urlparser: authority is not allowed in magnet link")
+	}
+	if u.IsWebSocket() && u.Fragment != "" {
+		// RFC 6455 section 3
+		return fmt.Errorf("urlparser: fragment %q is not allowed in WebSocket url", u.Fragment)
+	}
+	return validateChars("fragment", u.Fragment, isQueryChar)
+}
+
+// HasOverlongEncoding reports whether the percent-decoded path is not
+// valid UTF-8, like the overlong "%c0%af" encoding of "/" used for
+// path traversal. Malformed escapes are ignored, see Validate.
+func (u *URL) HasOverlongEncoding() bool {
+	if !strings.Contains(u.Path, "%") {
+		return false
+	}
+
+	decoded := make([]byte, 0, len(u.Path))
+	for i := 0; i < len(u.Path); i++ {
+		if u.Path[i] == '%' && i+2 < len(u.Path) && isHex(u.Path[i+1]) && isHex(u.Path[i+2]) {
+			decoded = append(decoded, unhex(u.Path[i+1])<<4|unhex(u.Path[i+2]))
+			i += 2
+			continue
+		}
+		decoded = append(decoded, u.Path[i])
+	}
+	return !utf8.Valid(decoded)
+}
+
+// IsValidHierarchy reports whether the path fits the authority, RFC 3986
+// section 3.3: with authority the path is empty or begins with "/",
+// without it the path can't begin with "//". It's useful for URLs
+// constructed from components rather than parsed.
+func (u *URL) IsValidHierarchy() bool {
+	if u.DoubleSlash != "" || u.BuildAuthority() != "" {
+		return u.Path == "" || strings.HasPrefix(u.Path, "/")
+	}
+	return !strings.HasPrefix(u.Path, "//")
+}
+
+// scheme = ALPHA *( ALPHA / DIGIT / "+" / "-" / "." )
+func validateScheme(scheme string) error {
+	for i := 0; i < len(scheme); i++ {
+		c := scheme[i]
+		if isAlpha(c) || (i > 0 && (isDigit(c) || c == '+' || c == '-' || c == '.')) {
+			continue
+		}
+		return fmt.Errorf("urlparser: invalid character %q in scheme %q", c, scheme)
+	}
+	return nil
+}
+
+// host = IP-literal / IPv4address / reg-name
+func validateHost(host string) error {
+	// comma is a valid sub-delim, but both comma and space mostly come
+	// from host lists like "a.com, b.com" in proxy configs
+	if strings.ContainsAny(host, " ,") {
+		return fmt.Errorf("urlparser: host %q contains a list separator, only a single host is allowed", host)
+	}
+	if strings.Contains(host, ":") {
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("urlparser: invalid IP literal %q in host", host)
+		}
+		return nil
+	}
+	return validateChars("host", host, isRegNameChar)
+}
+
+// validateChars checks that value consists of pct-encoded triplets and
+// bytes accepted by allowed.
+func validateChars(component, value string, allowed func(byte) bool) error {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '%' {
+			if i+2 >= len(value) || !isHex(value[i+1]) || !isHex(value[i+2]) {
+				return fmt.Errorf("urlparser: invalid percent-encoding in %s %q", component, value)
+			}
+			i += 2
+			continue
+		}
+		if !allowed(c) {
+			return fmt.Errorf("urlparser: invalid character %q in %s %q", c, component, value)
+		}
+	}
+	return nil
+}
+
+func isAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+func isUnreserved(c byte) bool {
+	return isAlpha(c) || isDigit(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// sub-delims = "!" / "$" / "&" / "'" / "(" / ")" / "*" / "+" / "," / ";" / "="
+func isSubDelim(c byte) bool {
+	return strings.IndexByte("!$&'()*+,;=", c) != -1
+}
+
+func isRegNameChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c)
+}
+
+func isUserinfoChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || c == ':'
+}
+
+// pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
+func isPathChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || c == ':' || c == '@' || c == '/'
+}
+
+// query = fragment = *( pchar / "/" / "?" )
+func isQueryChar(c byte) bool {
+	return isPathChar(c) || c == '?'
+}